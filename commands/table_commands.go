@@ -1,7 +1,167 @@
 package commands
 
+import "errors"
+
 type TableCommands struct {
-	Name    string            `yaml:"name"`
-	Count   int               `yaml:"count"`
-	Columns map[string]string `yaml:"columns"`
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+
+	// BatchSize is the most rows generated and written at a time, including
+	// in a single INSERT, in place of the batch size set for every table.
+	// Only the values of unique constraints and a sample of the rows other
+	// tables reference are kept from one batch to the next.
+	BatchSize int `yaml:"batchSize"`
+
+	Columns map[string]ColumnCommands `yaml:"columns"`
+}
+
+// ColumnCommands configures the data generated for a column. A column set to
+// a plain string uses it as the name of the generator, so these are the same:
+//
+//	columns:
+//	  name: firstname
+//	  name:
+//	    generator: firstname
+type ColumnCommands struct {
+	Generator GeneratorCommands `yaml:"generator"`
+
+	// Template makes the values of a column by combining generators,
+	// literals and the values of the other columns in the same row, like
+	// "{{lower .first_name}}.{{lower .last_name}}@example.com"
+	Template string `yaml:"template"`
+
+	// Regex is a pattern the values of a text column are made to match
+	Regex string `yaml:"regex"`
+
+	// Min and Max bound the values of numeric, date and timestamp columns.
+	// Dates and timestamps take a date like 2024-01-31, a timestamp, now or
+	// a time relative to now like -90d.
+	Min string `yaml:"min"`
+	Max string `yaml:"max"`
+
+	// Distribution shapes how the values of numeric, date and timestamp
+	// columns are spread between their bounds
+	Distribution *DistributionCommands `yaml:"distribution"`
+
+	// Weights sets how likely each value of an enum column is to be picked
+	// relative to the others
+	Weights map[string]float64 `yaml:"weights"`
+
+	Array *ArrayCommands `yaml:"array"`
+
+	// NullRatio is the chance of a nullable column being NULL, from 0 to 1,
+	// in place of the ratio set for every column
+	NullRatio *float64 `yaml:"nullRatio"`
+
+	// Default is how a column with a default value is filled in: generate
+	// a value for it, write DEFAULT or omit the column from the INSERT
+	Default string `yaml:"default"`
+
+	// Identity is how an identity or serial column is filled in: let the
+	// database pick the values or generate explicit ones
+	Identity string `yaml:"identity"`
+}
+
+// ArrayCommands sets the shape of the arrays generated for an array column.
+// The length applies to each dimension of multidimensional arrays.
+type ArrayCommands struct {
+	MinLength *int `yaml:"minLength"`
+	MaxLength *int `yaml:"maxLength"`
+
+	// NullRatio is the chance of an element being NULL, from 0 to 1
+	NullRatio float64 `yaml:"nullRatio"`
+}
+
+func (cc *ColumnCommands) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var generator string
+	if err := unmarshal(&generator); err == nil {
+		cc.Generator.Name = generator
+		return nil
+	}
+
+	type columnCommands ColumnCommands // avoid recursing back into this method
+	return unmarshal((*columnCommands)(cc))
+}
+
+// GeneratorCommands names the generator of a column along with the
+// parameters passed to it. A generator without parameters can be given by
+// name alone, otherwise its name is mapped to its parameters:
+//
+//	generator: sentence
+//	generator:
+//	  sentence:
+//	    wordCount: 8
+type GeneratorCommands struct {
+	Name   string
+	Params map[string]any
+}
+
+func (gc *GeneratorCommands) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&gc.Name); err == nil {
+		return nil
+	}
+
+	var generators map[string]map[string]any
+	if err := unmarshal(&generators); err != nil {
+		return err
+	}
+
+	if len(generators) != 1 {
+		return errors.New("a generator must be given by its name or its name mapped to its parameters")
+	}
+
+	for name, params := range generators {
+		gc.Name, gc.Params = name, params
+	}
+
+	return nil
+}
+
+// DistributionCommands picks how values are spread between a column's bounds.
+// A distribution without settings can be given by type alone, so these are
+// the same:
+//
+//	distribution: normal
+//	distribution:
+//	  type: normal
+//
+// Means and bucket bounds of dates and timestamps are given like min and
+// max, and standard deviations like 7d.
+type DistributionCommands struct {
+	// Type is one of uniform, normal, exponential, zipf or buckets
+	Type string `yaml:"type"`
+
+	Mean   string `yaml:"mean"`
+	StdDev string `yaml:"stdDev"`
+
+	// Rate is how quickly an exponential distribution falls away across the
+	// bounds, defaulting to 5
+	Rate float64 `yaml:"rate"`
+
+	// S and V shape a Zipf distribution, defaulting to 2 and 1
+	S float64 `yaml:"s"`
+	V float64 `yaml:"v"`
+
+	// Toward is the bound exponential and Zipf distributions favour, min
+	// or max, defaulting to min
+	Toward string `yaml:"toward"`
+
+	Buckets []BucketCommands `yaml:"buckets"`
+}
+
+// BucketCommands is a range of values picked relative to the other buckets
+// by weight
+type BucketCommands struct {
+	Min    string  `yaml:"min"`
+	Max    string  `yaml:"max"`
+	Weight float64 `yaml:"weight"`
+}
+
+func (dc *DistributionCommands) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&dc.Type); err == nil {
+		return nil
+	}
+
+	type distributionCommands DistributionCommands // avoid recursing back into this method
+	return unmarshal((*distributionCommands)(dc))
 }
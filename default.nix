@@ -9,5 +9,5 @@ pkgs.buildGoModule {
   # In 'nix develop', we don't need a copy of the source tree
   # in the Nix store.
   src = ./.;
-  vendorHash = "sha256-Y+E58mWniwSjDQOV4gF7LqTm/4GGNygsw7Ils+atAsk=";
+  vendorHash = "sha256-yZH7Mfoa2xFevD7bNUmRyXgr2o6bE1HMx86Zg7vqqv4=";
 }
@@ -40,7 +40,7 @@
               "-- user: postgres"
               "-- seed: 1"
               ""
-              "INSERT INTO todos (id,task,complete,created_at) VALUES (DEFAULT,'Change.',false,'2015-04-29'),(DEFAULT,'Without.',true,'1954-01-27'),(DEFAULT,'Tomorrow.',true,'1917-05-22');"
+              "INSERT INTO testing.todos (id,task,complete,created_at) VALUES (DEFAULT,'Change.',false,'2015-04-29'),(DEFAULT,'Without.',true,'1954-01-27'),(DEFAULT,'Tomorrow.',true,'1917-05-22');"
               ""
             ]
           );
@@ -62,6 +62,13 @@
               actual = client.succeed('dummy --path /etc/dummy.yml')
 
               assert expected == actual, "actual:\n" + actual + "\nexpected:\n" + expected
+
+              # Inserting the rows fails if the columns of the composite
+              # foreign key are paired up with the wrong referenced columns
+              client.succeed('dummy --path /etc/dummy-fk.yml --apply')
+              tasks = postgres.succeed("psql -U postgres -tAc 'SELECT count(*) FROM testing.tasks'").strip()
+
+              assert tasks == "10", "expected 10 tasks, got " + tasks
             '';
           };
         }
@@ -0,0 +1,120 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
+)
+
+// numericDatatypes are the datatypes of the columns that hold numbers
+var numericDatatypes = []string{"bigint", "integer", "mediumint", "serial", "smallint", "tinyint", "numeric", "decimal", "real", "double precision"}
+
+// Every function in gofakeit's catalog whose values suit a column is made
+// available as a generator under the same name
+func init() {
+	for name, info := range gofakeit.FuncLookups {
+		datatypes := catalogDatatypes(info)
+		if datatypes == nil {
+			continue
+		}
+
+		var params []string
+		for _, param := range info.Params {
+			params = append(params, param.Field)
+		}
+
+		Register(name, Generator{
+			Datatypes: datatypes,
+			Params:    params,
+			Generate: func(f *gofakeit.Faker, col Column, params *gofakeit.MapParams) (Value, error) {
+				value, err := info.Generate(f, params, &info)
+				if err != nil {
+					return nil, err
+				}
+
+				return catalogValue(col, value), nil
+			},
+		})
+	}
+
+	// Some of the strings suit more specific datatypes than text
+	allow("uuid", "uuid")
+	allow("ipv4address", "inet")
+	allow("ipv6address", "inet")
+	allow("macaddress", "macaddr")
+
+	Register("ipv4", generators["ipv4address"])
+}
+
+// allow lets a generator be used on columns of more datatypes
+func allow(name string, datatypes ...string) {
+	generator := generators[name]
+	generator.Datatypes = append(datatypes, generator.Datatypes...)
+	generators[name] = generator
+}
+
+// catalogDatatypes returns the datatypes of the columns that can hold the
+// values of one of gofakeit's functions, or nil if none can
+func catalogDatatypes(info gofakeit.Info) []string {
+	switch info.Output {
+	case "string":
+		return textDatatypes
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return numericDatatypes
+	case "float32", "float64":
+		return []string{"numeric", "decimal", "real", "double precision"}
+	case "bool":
+		return []string{"boolean"}
+	case "time":
+		return []string{"date", "timestamp with time zone", "timestamp without time zone", "timestamp"}
+	case "[]byte":
+		switch {
+		case info.ContentType == "application/json":
+			return append([]string{"json", "jsonb"}, textDatatypes...)
+		case info.ContentType == "application/xml":
+			return append([]string{"xml"}, textDatatypes...)
+		case strings.HasPrefix(info.ContentType, "image/"):
+			return []string{"bytea"}
+		default:
+			return textDatatypes
+		}
+	default:
+		return nil
+	}
+}
+
+// catalogValue converts a value from one of gofakeit's functions into the
+// value written for a column
+func catalogValue(col Column, value any) Value {
+	switch value := value.(type) {
+	case string, bool:
+		return value
+	case []byte:
+		if col.DataType == "bytea" {
+			return value
+		}
+
+		return string(value)
+	case time.Time:
+		switch col.DataType {
+		case "date":
+			return value.Format(time.DateOnly)
+		case "timestamp with time zone":
+			return value.Format(time.RFC3339)
+		default:
+			return value.Format(time.DateTime)
+		}
+	case float32:
+		return Number(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	case float64:
+		return Number(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return Number(fmt.Sprint(value))
+	}
+}
@@ -0,0 +1,282 @@
+package generate
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
+)
+
+// The ways values can be spread between a column's bounds
+const (
+	DistributionUniform     = "uniform"     // every value is as likely
+	DistributionNormal      = "normal"      // values cluster around a mean
+	DistributionExponential = "exponential" // values fall away from one bound
+	DistributionZipf        = "zipf"        // a few values near one bound are picked most
+	DistributionBuckets     = "buckets"     // ranges of values picked by weight
+)
+
+// IsDistribution checks if the distribution is one values can be spread by.
+func IsDistribution(distribution string) bool {
+	switch distribution {
+	case DistributionUniform, DistributionNormal, DistributionExponential, DistributionZipf, DistributionBuckets:
+		return true
+	default:
+		return false
+	}
+}
+
+// Distribution shapes how values are spread between a column's bounds. The
+// values are numbers for numeric columns and seconds since the Unix epoch for
+// dates and timestamps.
+type Distribution struct {
+	Type string
+
+	// Mean and StdDev place a normal distribution, with a nil Mean and a
+	// StdDev of 0 falling back on the middle of the bounds and a sixth of
+	// the distance between them
+	Mean   *float64
+	StdDev float64
+
+	// Rate is how quickly an exponential distribution falls away across
+	// the bounds
+	Rate float64
+
+	// S and V shape a Zipf distribution, where the chance of a value k
+	// steps from the favoured bound is proportional to (v + k)^-s
+	S float64
+	V float64
+
+	// TowardMax has exponential and Zipf distributions favour the upper
+	// bound rather than the lower one
+	TowardMax bool
+
+	Buckets []Bucket
+}
+
+// Bucket is a range of values picked relative to the other buckets by weight
+type Bucket struct {
+	Min    float64
+	Max    float64
+	Weight float64
+}
+
+// IsTemporal checks if the datatype holds dates or timestamps.
+func IsTemporal(datatype string) bool {
+	return datatype == "date" || datatype == "timestamp with time zone" || datatype == "timestamp without time zone" || datatype == "timestamp"
+}
+
+// timestampRange is the range of times MySQL's timestamp can hold, less a
+// day on either side so they stay within it in any session time zone
+var timestampRange = [2]time.Time{
+	time.Date(1970, time.January, 2, 0, 0, 0, 0, time.UTC),
+	time.Date(2038, time.January, 18, 0, 0, 0, 0, time.UTC),
+}
+
+// relativeTime matches a time relative to now like -90d or +2w
+var relativeTime = regexp.MustCompile(`^([+-]?)(\d+(?:\.\d+)?)([smhdwy])$`)
+
+// units holds the seconds in each of the units of relative times and spans
+var units = map[string]float64{
+	"s": 1,
+	"m": 60,
+	"h": 60 * 60,
+	"d": 24 * 60 * 60,
+	"w": 7 * 24 * 60 * 60,
+	"y": 365 * 24 * 60 * 60,
+}
+
+// ParseBound reads a value in the units of a column's distribution. Dates
+// and timestamps take a date like 2024-01-31, a timestamp, now or a time
+// relative to now like -90d.
+func ParseBound(col Column, text string) (float64, error) {
+	if IsNumeric(col.DataType) {
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, errors.New("Column '" + col.Name + "' is given a value that isn't a number: " + text)
+		}
+
+		return n, nil
+	}
+
+	if !IsTemporal(col.DataType) {
+		return 0, errors.New("Column '" + col.Name + "' of type " + col.DataType + " can't be limited to a range of values")
+	}
+
+	if text == "now" {
+		return float64(now().Unix()), nil
+	}
+
+	if match := relativeTime.FindStringSubmatch(text); match != nil {
+		n, _ := strconv.ParseFloat(match[2], 64)
+		if match[1] == "-" {
+			n = -n
+		}
+
+		return float64(now().Unix()) + n*units[match[3]], nil
+	}
+
+	for _, layout := range []string{time.DateOnly, time.DateTime, time.RFC3339} {
+		t, err := time.Parse(layout, text)
+		if err == nil {
+			return float64(t.Unix()), nil
+		}
+	}
+
+	return 0, errors.New("Column '" + col.Name + "' is given a time that can't be read: " + text)
+}
+
+// ParseSpan reads a distance between values in the units of a column's
+// distribution. Dates and timestamps take a length of time like 7d.
+func ParseSpan(col Column, text string) (float64, error) {
+	if !IsTemporal(col.DataType) {
+		return ParseBound(col, text)
+	}
+
+	match := relativeTime.FindStringSubmatch(text)
+	if match == nil || match[1] != "" {
+		return 0, errors.New("Column '" + col.Name + "' is given a length of time that can't be read: " + text)
+	}
+
+	n, _ := strconv.ParseFloat(match[2], 64)
+	return n * units[match[3]], nil
+}
+
+// spread picks a value between low and high following the distribution.
+// Zipf distributions count whole steps of the given unit from the favoured
+// bound. Values may fall outside of the bounds for buckets that do.
+func spread(low, high float64, distribution *Distribution, unit float64) float64 {
+	if distribution == nil {
+		return gofakeit.Float64Range(low, high)
+	}
+
+	// Place a fraction of the way across the bounds, from the favoured one
+	across := func(fraction float64) float64 {
+		if distribution.TowardMax {
+			return high - fraction*(high-low)
+		}
+
+		return low + fraction*(high-low)
+	}
+
+	switch distribution.Type {
+	case DistributionNormal:
+		mean := low + (high-low)/2
+		if distribution.Mean != nil {
+			mean = *distribution.Mean
+		}
+
+		stdDev := distribution.StdDev
+		if stdDev == 0 {
+			stdDev = (high - low) / 6
+		}
+
+		r := rand.New(gofakeit.GlobalFaker.Rand)
+		for range 100 {
+			n := mean + stdDev*r.NormFloat64()
+			if n >= low && n <= high {
+				return n
+			}
+		}
+
+		return min(max(mean, low), high)
+	case DistributionExponential:
+		rate := distribution.Rate
+		if rate == 0 {
+			rate = 5
+		}
+
+		// Invert the CDF of an exponential cut off at the far bound
+		u := gofakeit.Float64()
+		return across(-math.Log(1-u*(1-math.Exp(-rate))) / rate)
+	case DistributionZipf:
+		s, v := distribution.S, distribution.V
+		if s == 0 {
+			s = 2
+		}
+		if v == 0 {
+			v = 1
+		}
+
+		steps := uint64(1 << 63)
+		if span := (high - low) / unit; span < float64(steps) {
+			steps = uint64(span)
+		}
+
+		k := rand.NewZipf(rand.New(gofakeit.GlobalFaker.Rand), s, v, steps).Uint64()
+		if high == low {
+			return low
+		}
+
+		return across(float64(k) * unit / (high - low))
+	case DistributionBuckets:
+		total := 0.0
+		for _, bucket := range distribution.Buckets {
+			total += bucket.Weight
+		}
+
+		n := gofakeit.Float64Range(0, total)
+		for i, bucket := range distribution.Buckets {
+			n -= bucket.Weight
+			if n < 0 || i == len(distribution.Buckets)-1 {
+				return gofakeit.Float64Range(bucket.Min, bucket.Max)
+			}
+		}
+
+		return low
+	default:
+		return gofakeit.Float64Range(low, high)
+	}
+}
+
+// earliest is where dates are picked from when a column has no lower bound
+var earliest = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// moment picks a date or time between the bounds of a date or timestamp
+// column following its distribution. Dates are picked in whole days.
+func moment(col Column, options *Options) (Value, error) {
+	low, high := float64(earliest.Unix()), float64(now().Unix())
+	if options.Min != nil {
+		low = options.Min.Value
+	}
+	if options.Max != nil {
+		high = options.Max.Value
+	}
+
+	if col.DataType == "timestamp" {
+		low = max(low, float64(timestampRange[0].Unix()))
+		high = min(high, float64(timestampRange[1].Unix()))
+	}
+
+	if low > high {
+		return nil, errors.New("Column '" + col.Name + "' has no values of type " + col.DataType + " within its bounds")
+	}
+
+	unit := 1.0
+	if col.DataType == "date" {
+		unit = units["d"]
+	}
+
+	seconds := min(max(spread(low, high, options.Distribution, unit), low), high)
+	return formatMoment(col, time.Unix(int64(seconds), 0).UTC()), nil
+}
+
+// formatMoment writes a time the way a date or timestamp column holds it,
+// keeping the time of day of timestamps so they stay within their bounds
+func formatMoment(col Column, t time.Time) Value {
+	switch col.DataType {
+	case "date":
+		return t.Format(time.DateOnly)
+	case "timestamp with time zone":
+		return t.Format(time.RFC3339)
+	default:
+		return t.Format(time.DateTime)
+	}
+}
@@ -2,95 +2,287 @@ package generate
 
 import (
 	"errors"
+	"fmt"
+	"html"
 	"math"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
 )
 
-func FakeData(datatype, udt, columnName string, customData *map[string]string) (string, error) {
-	switch datatype {
-	case "ARRAY":
-		underlyingDt, err := udtToPsqlDatatype(udt)
-		if err != nil {
-			return "", err
+// now marks the end of the range that dates are picked from
+var now = time.Now
+
+// Options customises the data generated for a column
+type Options struct {
+	Generator string
+
+	// GeneratorParams holds the parameters passed to the generator
+	GeneratorParams *gofakeit.MapParams
+
+	// Regex is the pattern the values of a text column match
+	Regex *regexp.Regexp
+
+	// Template makes the column's values from other generators and the
+	// values of the other columns in the same row
+	Template *template.Template
+
+	Weights map[string]float64
+	Array   *ArrayOptions
+
+	// Values limits the column to a list of values
+	Values []Value
+
+	// Min and Max bound the values of numeric, date and timestamp columns,
+	// with dates and timestamps given in seconds since the Unix epoch
+	Min *Bound
+	Max *Bound
+
+	// Distribution shapes how values are spread between Min and Max, with
+	// nil spreading them evenly
+	Distribution *Distribution
+
+	// MinLength and MaxLength bound the number of characters in text
+	// columns, with 0 leaving them unbounded
+	MinLength int
+	MaxLength int
+
+	// NullRatio is the chance of a nullable column being left NULL
+	// instead of generating a value for it
+	NullRatio *float64
+
+	// Default is how the column is filled in when it has a default value
+	Default string
+
+	// Identity is how the column is filled in when it's an identity or
+	// serial column
+	Identity string
+}
+
+// Bound is the smallest or largest value a column may hold
+type Bound struct {
+	Value     float64
+	Exclusive bool
+}
+
+// ArrayOptions sets the number of elements in each dimension of an array
+// and how often an element is NULL
+type ArrayOptions struct {
+	MinLength int
+	MaxLength int
+	NullRatio float64
+}
+
+// DefaultArrayOptions is used for array columns without any options
+var DefaultArrayOptions = ArrayOptions{MinLength: 1, MaxLength: 3}
+
+func FakeData(col Column, options *Options) (Value, error) {
+	if options != nil && len(options.Values) > 0 {
+		return options.Values[gofakeit.IntRange(0, len(options.Values)-1)], nil
+	}
+
+	if options != nil && (options.Min != nil || options.Max != nil || options.Distribution != nil) {
+		if IsNumeric(col.DataType) {
+			return number(col, options.Min, options.Max, options.Distribution)
+		} else if IsTemporal(col.DataType) {
+			return moment(col, options)
 		}
+	}
 
-		value, err := FakeData(underlyingDt, "", columnName, customData)
+	if options != nil && options.Regex != nil && slices.Contains(textDatatypes, col.DataType) {
+		return matching(col, options)
+	}
+
+	// Array columns use the generator for each of their elements
+	if options != nil && options.Generator != "" && col.DataType != "ARRAY" {
+		value, err := runGenerator(col, options.Generator, options.GeneratorParams)
 		if err != nil {
-			return "", err
+			return nil, err
+		}
+
+		if text, ok := value.(string); ok && slices.Contains(textDatatypes, col.DataType) {
+			return fitText(col, options, text), nil
 		}
 
-		var array strings.Builder
-		array.WriteString("ARRAY[")
-		array.WriteString(value)
-		array.WriteString("]")
-		return array.String(), nil
+		return value, nil
+	}
+
+	switch col.DataType {
+	case "ARRAY":
+		return array(col, options)
 	case "bigint":
-		bigIntVal := gofakeit.Int64()
-		return strconv.FormatInt(bigIntVal, 10), nil
-	case "bit":
+		return gofakeit.Int64(), nil
+	case "bit", "bit varying":
 		charset := "01"
-		val := make([]byte, 8)
+		length := 8
+		if col.CharacterMaximumLength.Valid {
+			length = int(col.CharacterMaximumLength.Int32)
+		}
+
+		// Varying bit strings can be any length up to the maximum
+		if col.DataType == "bit varying" {
+			length = gofakeit.IntRange(1, length)
+		}
+
+		val := make([]byte, length)
 		for i := range val {
 			val[i] = charset[gofakeit.IntRange(0, len(charset)-1)]
 		}
-		var bitString strings.Builder
-		bitString.WriteString("B'")
-		bitString.WriteString(string(val))
-		bitString.WriteString("'")
-		return bitString.String(), nil
-	case "boolean":
-		boolVal := gofakeit.Bool()
-		if boolVal {
-			return "true", nil
-		} else {
-			return "false", nil
+		return Bits(val), nil
+	case "box":
+		return "(" + point() + "," + point() + ")", nil
+	case "bytea":
+		bytes := make([]byte, gofakeit.IntRange(1, 32))
+		for i := range bytes {
+			bytes[i] = byte(gofakeit.IntRange(0, 255))
+		}
+		return bytes, nil
+	case "cidr":
+		octets := strings.Split(gofakeit.IPv4Address(), ".")
+		return strings.Join(octets[:3], ".") + ".0/24", nil
+	case "circle":
+		return "<" + point() + "," + strconv.FormatFloat(gofakeit.Float64Range(1, 100), 'f', 2, 64) + ">", nil
+	case "date":
+		return date().Format(time.DateOnly), nil
+	case "daterange":
+		start := date()
+		end := start.AddDate(0, 0, gofakeit.IntRange(1, 365))
+		return "[" + start.Format(time.DateOnly) + "," + end.Format(time.DateOnly) + ")", nil
+	case "inet":
+		return gofakeit.IPv4Address(), nil
+	case "int4range", "int8range":
+		start := gofakeit.IntRange(-1000000, 1000000)
+		end := start + gofakeit.IntRange(1, 1000000)
+		return "[" + strconv.Itoa(start) + "," + strconv.Itoa(end) + ")", nil
+	case "interval":
+		return fmt.Sprintf("%d days %02d:%02d:%02d", gofakeit.IntRange(0, 365), gofakeit.Hour(), gofakeit.Minute(), gofakeit.Second()), nil
+	case "line":
+		// A and B can't both be zero
+		a := strconv.FormatFloat(gofakeit.Float64Range(1, 100), 'f', 2, 64)
+		b := strconv.FormatFloat(gofakeit.Float64Range(-100, 100), 'f', 2, 64)
+		c := strconv.FormatFloat(gofakeit.Float64Range(-100, 100), 'f', 2, 64)
+		return "{" + a + "," + b + "," + c + "}", nil
+	case "lseg":
+		return "[" + point() + "," + point() + "]", nil
+	case "macaddr":
+		return gofakeit.MacAddress(), nil
+	case "macaddr8":
+		octets := make([]string, 8)
+		for i := range octets {
+			octets[i] = fmt.Sprintf("%02x", gofakeit.IntRange(0, 255))
+		}
+		return strings.Join(octets, ":"), nil
+	case "money":
+		return strconv.FormatFloat(gofakeit.Price(0, 10000), 'f', 2, 64), nil
+	case "numrange":
+		start := gofakeit.Float64Range(-1000, 1000)
+		end := start + gofakeit.Float64Range(0.01, 1000)
+		return "[" + strconv.FormatFloat(start, 'f', 2, 64) + "," + strconv.FormatFloat(end, 'f', 2, 64) + ")", nil
+	case "oid":
+		return int64(gofakeit.IntRange(1, math.MaxInt32)), nil
+	case "path":
+		points := make([]string, gofakeit.IntRange(2, 5))
+		for i := range points {
+			points[i] = point()
+		}
+		return "[" + strings.Join(points, ",") + "]", nil
+	case "pg_lsn":
+		return fmt.Sprintf("%X/%X", gofakeit.IntRange(0, 255), gofakeit.IntRange(0, math.MaxInt32)), nil
+	case "point":
+		return point(), nil
+	case "polygon":
+		points := make([]string, gofakeit.IntRange(3, 6))
+		for i := range points {
+			points[i] = point()
 		}
+		return "(" + strings.Join(points, ",") + ")", nil
+	case "time without time zone":
+		return fmt.Sprintf("%02d:%02d:%02d", gofakeit.Hour(), gofakeit.Minute(), gofakeit.Second()), nil
+	case "time with time zone":
+		return fmt.Sprintf("%02d:%02d:%02d+00", gofakeit.Hour(), gofakeit.Minute(), gofakeit.Second()), nil
+	case "tsrange", "tstzrange":
+		start := date()
+		end := start.Add(time.Duration(gofakeit.IntRange(1, 24*365)) * time.Hour)
+		return "[" + start.Format(time.DateTime) + "," + end.Format(time.DateTime) + ")", nil
+	case "tsquery":
+		return word() + " & " + word(), nil
+	case "tsvector":
+		words := make([]string, gofakeit.IntRange(1, 5))
+		for i := range words {
+			words[i] = word()
+		}
+		return strings.Join(words, " "), nil
+	case "xml":
+		return "<note><from>" + html.EscapeString(gofakeit.Name()) + "</from><body>" + html.EscapeString(gofakeit.Sentence(5)) + "</body></note>", nil
+	case "boolean":
+		return gofakeit.Bool(), nil
 	case "numeric", "decimal":
 		charset := "0123456789"
-		maxPreDeicmalLen := 131072
+		maxPreDecimalLen := 131072
 		maxPostDecimalLen := 16383
-		preDecimalLen := gofakeit.IntRange(1, maxPreDeicmalLen)
-		postDecimalLen := gofakeit.IntRange(1, maxPostDecimalLen)
+		minPostDecimalLen := 1
 
-		val := make([]byte, preDecimalLen+1+postDecimalLen) // +1 for the decimal character
-		i := 0
-		j := 0
+		// Stay within numeric(precision, scale) when it's been declared
+		if col.NumericPrecision.Valid {
+			scale := max(int(col.NumericScale.Int32), 0)
+			maxPreDecimalLen = max(int(col.NumericPrecision.Int32)-scale, 0)
+			maxPostDecimalLen = scale
+			minPostDecimalLen = scale
+		}
 
-		for j < preDecimalLen {
-			val[i] = charset[gofakeit.IntRange(0, len(charset)-1)]
-			i += 1
-			j += 1
+		preDecimalLen := gofakeit.IntRange(1, max(maxPreDecimalLen, 1))
+		postDecimalLen := gofakeit.IntRange(minPostDecimalLen, maxPostDecimalLen)
+
+		var val strings.Builder
+		if maxPreDecimalLen == 0 {
+			val.WriteRune('0')
+		} else {
+			for range preDecimalLen {
+				val.WriteByte(charset[gofakeit.IntRange(0, len(charset)-1)])
+			}
 		}
 
-		val[i] = '.'
-		i += 1
+		if postDecimalLen > 0 {
+			val.WriteRune('.')
+			for range postDecimalLen {
+				val.WriteByte(charset[gofakeit.IntRange(0, len(charset)-1)])
+			}
+		}
 
-		j = 0
-		for j < postDecimalLen {
-			val[i] = charset[gofakeit.IntRange(0, len(charset)-1)]
-			i += 1
-			j += 1
+		return Number(val.String()), nil
+	case "enum", "USER-DEFINED":
+		if len(col.EnumValues) == 0 {
+			return nil, errors.New("Datatype currently unsupported: " + col.DataType + "(" + col.UdtName + ")")
 		}
 
-		return string(val), nil
+		var weights map[string]float64
+		if options != nil {
+			weights = options.Weights
+		}
+
+		return pick(col.EnumValues, weights), nil
 	case "double precision":
 		// PSQL double precision has 15 digits of precision
-		val := strconv.FormatFloat(gofakeit.Float64(), 'f', 15, 64)
-		return val, nil
+		return Number(strconv.FormatFloat(gofakeit.Float64(), 'f', 15, 64)), nil
 	case "integer":
-		intVal := gofakeit.Int16()
-		return strconv.FormatInt(int64(intVal), 10), nil
+		return int64(gofakeit.Int16()), nil
+	case "mediumint":
+		return int64(gofakeit.IntRange(0, 8388607)), nil
 	case "json", "jsonb":
 		var jo gofakeit.JSONOptions
 
 		// Use gofakeit to create random JSON fields
 		err := gofakeit.Struct(&jo)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		// Overwrite the fields to force this to be an object
@@ -100,85 +292,593 @@ func FakeData(datatype, udt, columnName string, customData *map[string]string) (
 
 		jsonRaw, err := gofakeit.JSON(&jo)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		var json strings.Builder
-		json.WriteRune('\'')
-		json.WriteString(strings.ReplaceAll(string(jsonRaw), "'", "''")) // escape single quotes
-		json.WriteRune('\'')
-		return json.String(), err
+		return string(jsonRaw), nil
 	case "real":
 		// PSQL real has 6 digits of precision
-		val := strconv.FormatFloat(gofakeit.Float64(), 'f', 6, 32)
-		return val, nil
+		return Number(strconv.FormatFloat(gofakeit.Float64(), 'f', 6, 32)), nil
 	case "serial":
-		serialVal := gofakeit.IntRange(1, math.MaxInt32)
-		return strconv.FormatInt(int64(serialVal), 10), nil
-	case "smallint":
-		smallSerialVal := gofakeit.IntRange(1, math.MaxInt16)
-		return strconv.FormatInt(int64(smallSerialVal), 10), nil
-	case "text":
-		var sentence strings.Builder
-		sentence.WriteRune('\'')
-
-		// Check if the user has requested custom data
-		dataWritten := false
-		if customData != nil {
-			customData, ok := (*customData)[columnName]
-			if ok {
-				switch customData {
-				case "company":
-					sentence.WriteString(strings.ReplaceAll(gofakeit.Company(), "'", "''")) // escape single quotes
-				case "firstname":
-					sentence.WriteString(strings.ReplaceAll(gofakeit.FirstName(), "'", "''")) // escape single quotes
-				case "lastname":
-					sentence.WriteString(strings.ReplaceAll(gofakeit.LastName(), "'", "''")) // escape single quotes
-				case "name":
-					sentence.WriteString(strings.ReplaceAll(gofakeit.Name(), "'", "''")) // escape single quotes
-				case "uuid":
-					sentence.WriteString(gofakeit.UUID())
-				default:
-					panic("unrecognized custom 'text' datatype: \"" + customData + "\"")
-				}
-
-				dataWritten = true
+		return int64(gofakeit.IntRange(1, math.MaxInt32)), nil
+	case "set":
+		var members []string
+		for _, value := range col.EnumValues {
+			if gofakeit.Bool() {
+				members = append(members, value)
 			}
 		}
 
-		if !dataWritten {
-			sentence.WriteString(strings.ReplaceAll(gofakeit.Sentence(1), "'", "''")) // escape single quotes
-		}
-		sentence.WriteRune('\'')
-		return sentence.String(), nil
+		return strings.Join(members, ","), nil
+	case "smallint":
+		return int64(gofakeit.IntRange(1, math.MaxInt16)), nil
+	case "text", "character varying", "character", "name":
+		return fitText(col, options, gofakeit.Sentence(1)), nil
 	case "timestamp with time zone":
-		var timestamp strings.Builder
-		timestamp.WriteRune('\'')
-		timestamp.WriteString(gofakeit.Date().Format(time.RFC3339))
-		timestamp.WriteRune('\'')
-		return timestamp.String(), nil
+		return date().Format(time.RFC3339), nil
 	case "timestamp without time zone":
-		var timestamp strings.Builder
-		timestamp.WriteRune('\'')
-		timestamp.WriteString(gofakeit.Date().Format(time.DateOnly))
-		timestamp.WriteRune('\'')
-		return timestamp.String(), nil
+		return date().Format(time.DateOnly), nil
+	case "timestamp":
+		return gofakeit.DateRange(timestampRange[0], now()).Format(time.DateOnly), nil
+	case "tinyint":
+		return int64(gofakeit.IntRange(0, math.MaxInt8)), nil
 	case "uuid":
-		var uuid strings.Builder
-		uuid.WriteRune('\'')
-		uuid.WriteString(gofakeit.UUID())
-		uuid.WriteRune('\'')
-		return uuid.String(), nil
+		return gofakeit.UUID(), nil
+	case "year":
+		return int64(gofakeit.IntRange(1901, 2155)), nil
 	default:
-		return "", errors.New("Datatype currently unsupported: " + datatype + "(" + udt + ")")
+		return nil, errors.New("Datatype currently unsupported: " + col.DataType + "(" + col.UdtName + ")")
 	}
 }
 
-func udtToPsqlDatatype(udt string) (string, error) {
-	switch udt {
-	case "_text", "text":
-		return "text", nil
+// fitText lengthens or shortens the value of a text column to stay within
+// the column's declared length and the length asked for in its options
+func fitText(col Column, options *Options, value string) string {
+	if options != nil && options.MinLength > 0 {
+		for len([]rune(value)) < options.MinLength {
+			value += " " + gofakeit.Word()
+		}
+	}
+
+	if maxLength := textMaxLength(col, options); maxLength >= 0 {
+		value = truncate(value, maxLength)
+	}
+
+	return value
+}
+
+// textMaxLength returns the most characters a text column's values may have,
+// or -1 when there's no limit
+func textMaxLength(col Column, options *Options) int {
+	// Keep within the declared length of varchar(n) and char(n) columns
+	maxLength := -1
+	if col.CharacterMaximumLength.Valid {
+		maxLength = int(col.CharacterMaximumLength.Int32)
+	} else if col.DataType == "name" {
+		maxLength = 63
+	}
+
+	if options != nil && options.MaxLength > 0 && (maxLength < 0 || options.MaxLength < maxLength) {
+		maxLength = options.MaxLength
+	}
+
+	return maxLength
+}
+
+// Distinct makes the nth of a series of values for a column that are all
+// different from each other, for when random values keep colliding with the
+// ones already generated. Integer columns count up through the values within
+// their bounds and text columns have n added to the value, before the @ of an
+// email address, while staying within their length. It's false past the last
+// value or for columns whose values can't be counted.
+func Distinct(col Column, options *Options, value Value, n int64) (Value, bool) {
+	if n < 0 || options != nil && (len(options.Values) > 0 || options.Regex != nil) {
+		return nil, false
+	}
+
+	if defaults, ok := integerRanges[col.DataType]; ok {
+		var lower, upper *Bound
+		if options != nil {
+			lower, upper = options.Min, options.Max
+		}
+
+		limits := integerLimits[col.DataType]
+		low, high := integerBounds(lower, upper, 1, defaults[0], defaults[1], limits[0], limits[1])
+
+		// The span of a bigint overflows, but then there's no running out
+		if span := high - low; low > high || span >= 0 && n > span {
+			return nil, false
+		}
+
+		return low + n, true
+	}
+
+	text, ok := value.(string)
+	if !ok || !slices.Contains(textDatatypes, col.DataType) {
+		return nil, false
+	}
+
+	suffix := strconv.FormatInt(n, 10)
+	var domain string
+	if at := strings.LastIndexByte(text, '@'); at > 0 {
+		text, domain = text[:at], text[at:]
+	}
+
+	if maxLength := textMaxLength(col, options); maxLength >= 0 {
+		room := maxLength - len(suffix) - len([]rune(domain))
+		if room < 0 {
+			// Drop the domain rather than the count
+			text, domain = text+domain, ""
+			room = maxLength - len(suffix)
+		}
+
+		if room < 0 {
+			return nil, false
+		}
+
+		text = truncate(text, room)
+	}
+
+	return text + suffix + domain, true
+}
+
+// matching creates a value for a text column that matches its pattern.
+// Values can't be shortened or lengthened without breaking the match so
+// values are created until one fits in the column.
+func matching(col Column, options *Options) (Value, error) {
+	maxLength := textMaxLength(col, options)
+	for range 100 {
+		value := gofakeit.Regex(options.Regex.String())
+		length := len([]rune(value))
+		if options.Regex.MatchString(value) && length >= options.MinLength && (maxLength < 0 || length <= maxLength) {
+			return value, nil
+		}
+	}
+
+	return nil, errors.New("Could not generate a value for column '" + col.Name + "' matching the pattern " + options.Regex.String() + " that fits in the column")
+}
+
+// date picks a time between the start of 1900 and the end of the current year
+func date() time.Time {
+	return time.Date(gofakeit.IntRange(1900, now().Year()), time.Month(gofakeit.IntRange(1, 12)), gofakeit.IntRange(1, 31),
+		gofakeit.Hour(), gofakeit.Minute(), gofakeit.Second(), gofakeit.NanoSecond(), time.UTC)
+}
+
+// pick chooses one of the values, favouring those with a larger weight.
+// Values without a weight are given a weight of 1.
+func pick(values []string, weights map[string]float64) string {
+	if len(weights) == 0 {
+		return values[gofakeit.IntRange(0, len(values)-1)]
+	}
+
+	total := 0.0
+	for _, value := range values {
+		total += weight(value, weights)
+	}
+
+	n := gofakeit.Float64Range(0, total)
+	for _, value := range values {
+		n -= weight(value, weights)
+		if n < 0 {
+			return value
+		}
+	}
+
+	// Only reached when n lands exactly on the total
+	for i := len(values) - 1; i >= 0; i-- {
+		if weight(values[i], weights) > 0 {
+			return values[i]
+		}
+	}
+
+	return values[len(values)-1]
+}
+
+func weight(value string, weights map[string]float64) float64 {
+	w, ok := weights[value]
+	if !ok {
+		return 1
+	}
+
+	return w
+}
+
+// point creates a geometric point for the geometric types
+func point() string {
+	x := strconv.FormatFloat(gofakeit.Float64Range(-1000, 1000), 'f', 2, 64)
+	y := strconv.FormatFloat(gofakeit.Float64Range(-1000, 1000), 'f', 2, 64)
+	return "(" + x + "," + y + ")"
+}
+
+// word creates a lowercase word that's safe to use as a text search lexeme
+func word() string {
+	return strings.ToLower(gofakeit.LetterN(uint(gofakeit.IntRange(3, 10))))
+}
+
+// IsNumeric checks if the datatype holds numbers.
+func IsNumeric(datatype string) bool {
+	switch datatype {
+	case "bigint", "integer", "mediumint", "serial", "smallint", "tinyint",
+		"numeric", "decimal", "real", "double precision":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsText checks if the datatype holds character strings.
+func IsText(datatype string) bool {
+	return datatype == "text" || datatype == "character varying" || datatype == "character"
+}
+
+// truncate shortens a string to at most length characters
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+
+	return string(runes[:length])
+}
+
+// udtDatatypes maps the UDT names of the built-in types onto the datatype
+// information_schema reports for them
+var udtDatatypes = map[string]string{
+	"bit":         "bit",
+	"bool":        "boolean",
+	"box":         "box",
+	"bpchar":      "character",
+	"bytea":       "bytea",
+	"cidr":        "cidr",
+	"circle":      "circle",
+	"date":        "date",
+	"daterange":   "daterange",
+	"float4":      "real",
+	"float8":      "double precision",
+	"inet":        "inet",
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int4range":   "int4range",
+	"int8":        "bigint",
+	"int8range":   "int8range",
+	"interval":    "interval",
+	"json":        "json",
+	"jsonb":       "jsonb",
+	"line":        "line",
+	"lseg":        "lseg",
+	"macaddr":     "macaddr",
+	"macaddr8":    "macaddr8",
+	"money":       "money",
+	"name":        "name",
+	"numeric":     "numeric",
+	"numrange":    "numrange",
+	"oid":         "oid",
+	"path":        "path",
+	"pg_lsn":      "pg_lsn",
+	"point":       "point",
+	"polygon":     "polygon",
+	"text":        "text",
+	"time":        "time without time zone",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"timetz":      "time with time zone",
+	"tsquery":     "tsquery",
+	"tsrange":     "tsrange",
+	"tstzrange":   "tstzrange",
+	"tsvector":    "tsvector",
+	"uuid":        "uuid",
+	"varbit":      "bit varying",
+	"varchar":     "character varying",
+	"xml":         "xml",
+}
+
+// udtToPsqlDatatype finds the datatype of the elements of an array column
+func udtToPsqlDatatype(col Column) (string, error) {
+	udt := strings.TrimPrefix(col.UdtName, "_")
+
+	datatype, ok := udtDatatypes[udt]
+	if ok {
+		return datatype, nil
+	}
+
+	// Arrays of enum types come with the enum's labels
+	if len(col.EnumValues) > 0 {
+		return "USER-DEFINED", nil
+	}
+
+	return "", errors.New("Unknown UDT to datatype mapping: " + col.UdtName)
+}
+
+// array creates an array for an array column. Postgres requires every
+// sub-array of a multidimensional array to have the same number of elements
+// so the length of each dimension is picked once up front.
+func array(col Column, options *Options) (Value, error) {
+	datatype, err := udtToPsqlDatatype(col)
+	if err != nil {
+		return nil, err
+	}
+
+	element := col
+	element.DataType = datatype
+	element.UdtName = strings.TrimPrefix(col.UdtName, "_")
+	element.ArrayDimensions = 0
+
+	shape := DefaultArrayOptions
+	if options != nil && options.Array != nil {
+		shape = *options.Array
+	}
+
+	dimensions := max(col.ArrayDimensions, 1)
+	lengths := make([]int, dimensions)
+	empty := false
+	for i := range lengths {
+		lengths[i] = gofakeit.IntRange(shape.MinLength, shape.MaxLength)
+		if lengths[i] == 0 {
+			empty = true
+		}
+	}
+
+	// Arrays are cast since Postgres can't tell the type of an empty array
+	// and won't convert text elements to most other types by itself
+	if empty {
+		return Array{ElementType: arrayType(element), Dimensions: dimensions, Elements: make([]Value, 0)}, nil
+	}
+
+	return arrayElements(element, options, lengths, shape.NullRatio)
+}
+
+// arrayElements creates the elements of an array, nesting a sub-array for
+// each of the dimensions after the first
+func arrayElements(element Column, options *Options, lengths []int, nullRatio float64) (Array, error) {
+	array := Array{ElementType: arrayType(element), Dimensions: len(lengths)}
+	for range lengths[0] {
+		if len(lengths) > 1 {
+			sub, err := arrayElements(element, options, lengths[1:], nullRatio)
+			if err != nil {
+				return Array{}, err
+			}
+
+			array.Elements = append(array.Elements, sub)
+			continue
+		}
+
+		if nullRatio > 0 && gofakeit.Float64() < nullRatio {
+			array.Elements = append(array.Elements, Null)
+			continue
+		}
+
+		value, err := FakeData(element, options)
+		if err != nil {
+			return Array{}, err
+		}
+
+		array.Elements = append(array.Elements, value)
+	}
+
+	return array, nil
+}
+
+// arrayType returns the name of an array's element type to cast the array
+// to, qualified by the type's schema when it's user defined
+func arrayType(element Column) string {
+	name := quoteIdentifier(element.UdtName)
+	if element.DataType == "USER-DEFINED" && element.UdtSchema != "" && element.UdtSchema != "pg_catalog" {
+		name = quoteIdentifier(element.UdtSchema) + "." + name
+	}
+
+	return name
+}
+
+// plainIdentifier matches the names Postgres reads back the same without
+// quotes
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// quoteIdentifier quotes a name unless Postgres would read it back the
+// same without quotes
+func quoteIdentifier(name string) string {
+	if plainIdentifier.MatchString(name) {
+		return name
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// boundedSpan is how far the values of a column with only one bound may
+// stray from it
+const boundedSpan = 1000000
+
+// integerRanges are the values picked for integer columns, which are also
+// used when a bound leaves one side open
+var integerRanges = map[string][2]int64{
+	"bigint":    {math.MinInt64, math.MaxInt64},
+	"integer":   {math.MinInt16, math.MaxInt16},
+	"mediumint": {0, 8388607},
+	"serial":    {1, math.MaxInt32},
+	"smallint":  {1, math.MaxInt16},
+	"tinyint":   {0, math.MaxInt8},
+}
+
+// integerLimits are the values integer columns can hold
+var integerLimits = map[string][2]int64{
+	"bigint":    {math.MinInt64, math.MaxInt64},
+	"integer":   {math.MinInt32, math.MaxInt32},
+	"mediumint": {-8388608, 8388607},
+	"serial":    {0, math.MaxInt32},
+	"smallint":  {math.MinInt16, math.MaxInt16},
+	"tinyint":   {math.MinInt8, math.MaxInt8},
+}
+
+// number picks a number between the bounds that fits in the column,
+// following the distribution when there is one
+func number(col Column, lower, upper *Bound, distribution *Distribution) (Value, error) {
+	switch col.DataType {
+	case "real", "double precision":
+		digits := 15
+		if col.DataType == "real" {
+			digits = 6
+		}
+
+		low, high := openRange(lower, upper, 0, 1)
+		for range 100 {
+			value := strconv.FormatFloat(min(max(spread(low, high, distribution, 1), low), high), 'f', digits, 64)
+
+			// Rounding may land on an excluded bound
+			n, _ := strconv.ParseFloat(value, 64)
+			if within(n, lower, upper) {
+				return Number(value), nil
+			}
+		}
+	case "numeric", "decimal":
+		scale := 2
+		precision := 18
+		if col.NumericPrecision.Valid {
+			scale = max(int(col.NumericScale.Int32), 0)
+			precision = min(int(col.NumericPrecision.Int32), 18)
+		}
+
+		// Work in the smallest unit the column can hold so the
+		// value is exact
+		units := min(scale, 9)
+		step := math.Pow10(units)
+		limit := int64(math.Pow10(max(precision-(scale-units), 0))) - 1
+		low, high := integerBounds(lower, upper, step, 0, int64(boundedSpan*step), -limit, limit)
+		if low > high {
+			break
+		}
+
+		value := spreadInt64(low, high, distribution, step)
+		return Number(formatUnits(value, units, scale)), nil
 	default:
-		return "", errors.New("Unknown UDT to datatype mapping: " + udt)
+		defaults, ok := integerRanges[col.DataType]
+		if !ok {
+			break
+		}
+
+		limits := integerLimits[col.DataType]
+		low, high := integerBounds(lower, upper, 1, defaults[0], defaults[1], limits[0], limits[1])
+		if low > high {
+			break
+		}
+
+		return spreadInt64(low, high, distribution, 1), nil
+	}
+
+	return nil, errors.New("Column '" + col.Name + "' has no values of type " + col.DataType + " within its bounds")
+}
+
+// openRange fills in the sides of a range that have been left open
+func openRange(lower, upper *Bound, low, high float64) (float64, float64) {
+	switch {
+	case lower == nil && upper == nil:
+		return low, high
+	case lower != nil && upper != nil:
+		return lower.Value, upper.Value
+	case lower != nil:
+		return lower.Value, lower.Value + boundedSpan
+	case upper.Value < low:
+		return upper.Value - boundedSpan, upper.Value
+	default:
+		return low, upper.Value
+	}
+}
+
+// integerBounds converts the bounds into a range of whole numbers of the
+// given step, falling back on the defaults and staying within the limits
+func integerBounds(lower, upper *Bound, step float64, low, high, lowest, highest int64) (int64, int64) {
+	if lower != nil {
+		n := math.Ceil(lower.Value * step)
+		if lower.Exclusive && n == lower.Value*step {
+			n += 1
+		}
+		low = float64ToInt(n)
+	}
+
+	if upper != nil {
+		n := math.Floor(upper.Value * step)
+		if upper.Exclusive && n == upper.Value*step {
+			n -= 1
+		}
+		high = float64ToInt(n)
+	}
+
+	// A bound can fall outside of the defaults for the other side
+	if low > high && lower == nil {
+		low = float64ToInt(float64(high) - boundedSpan*step)
+	} else if low > high && upper == nil {
+		high = float64ToInt(float64(low) + boundedSpan*step)
+	}
+
+	return max(low, lowest), min(high, highest)
+}
+
+// float64ToInt converts a whole number without overflowing
+func float64ToInt(n float64) int64 {
+	if n <= math.MinInt64 {
+		return math.MinInt64
+	} else if n >= math.MaxInt64 {
+		return math.MaxInt64
 	}
+
+	return int64(n)
+}
+
+// randomInt64 picks a number between low and high, even when the range is
+// too large for an int64
+func randomInt64(low, high int64) int64 {
+	span := uint64(high - low)
+	if span == math.MaxUint64 {
+		return int64(gofakeit.Uint64())
+	}
+
+	return low + int64(gofakeit.Uint64()%(span+1))
+}
+
+// spreadInt64 picks a whole number of units between low and high following
+// the distribution, whose values are in units of 1/step
+func spreadInt64(low, high int64, distribution *Distribution, step float64) int64 {
+	if distribution == nil || distribution.Type == DistributionUniform {
+		return randomInt64(low, high)
+	}
+
+	n := spread(float64(low)/step, float64(high)/step, distribution, 1)
+	return min(max(float64ToInt(math.Round(n*step)), low), high)
+}
+
+// formatUnits writes a number counted in units of 10^-units with the given
+// number of decimal places
+func formatUnits(value int64, units, scale int) string {
+	sign := ""
+	magnitude := uint64(value)
+	if value < 0 {
+		sign = "-"
+		magnitude = uint64(-value)
+	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	if units == 0 {
+		if scale == 0 {
+			return sign + digits
+		}
+		return sign + digits + "." + strings.Repeat("0", scale)
+	}
+
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+
+	point := len(digits) - units
+	return sign + digits[:point] + "." + digits[point:] + strings.Repeat("0", scale-units)
+}
+
+// within checks if a number lies between the bounds
+func within(n float64, lower, upper *Bound) bool {
+	if lower != nil && (n < lower.Value || (lower.Exclusive && n == lower.Value)) {
+		return false
+	}
+
+	if upper != nil && (n > upper.Value || (upper.Exclusive && n == upper.Value)) {
+		return false
+	}
+
+	return true
 }
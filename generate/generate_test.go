@@ -1,28 +1,40 @@
 package generate
 
 import (
+	"database/sql"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
 )
 
 func TestMain(m *testing.M) {
 	gofakeit.Seed(1) // pin the seed so we can test the outputs
+	now = func() time.Time { return time.Date(2025, time.April, 12, 10, 0, 0, 0, time.UTC) }
 	code := m.Run()
 	os.Exit(code)
 }
 
-func compare(t *testing.T, actual, expected string) {
-	if strings.Compare(actual, expected) != 0 {
-		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, actual)
+func fakeColumn(datatype, udt string) Column {
+	return Column{Name: "test", DataType: datatype, UdtName: udt}
+}
+
+func compare(t *testing.T, actual Value, expected string) {
+	if strings.Compare(Text(actual), expected) != 0 {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, Text(actual))
 	}
 }
 
 func TestBigInt(t *testing.T) {
 	expected := "9172393864939720632"
-	actual, err := FakeData("bigint", "int8", "test", nil)
+	actual, err := FakeData(fakeColumn("bigint", "int8"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(bigint, int8)"`)
@@ -32,8 +44,8 @@ func TestBigInt(t *testing.T) {
 }
 
 func TestBitString(t *testing.T) {
-	expected := "B'10010011'"
-	actual, err := FakeData("bit", "bit", "test", nil)
+	expected := "10010011"
+	actual, err := FakeData(fakeColumn("bit", "bit"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(bit, bit)"`)
@@ -44,7 +56,7 @@ func TestBitString(t *testing.T) {
 
 func TestBoolean(t *testing.T) {
 	expected := "false"
-	actual, err := FakeData("boolean", "bool", "test", nil)
+	actual, err := FakeData(fakeColumn("boolean", "bool"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(boolean, bool)"`)
@@ -55,7 +67,7 @@ func TestBoolean(t *testing.T) {
 
 func TestDoublePrecision(t *testing.T) {
 	expected := "0.235012593156176"
-	actual, err := FakeData("double precision", "float8", "test", nil)
+	actual, err := FakeData(fakeColumn("double precision", "float8"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(double precision, float8)"`)
@@ -66,7 +78,7 @@ func TestDoublePrecision(t *testing.T) {
 
 func TestInteger(t *testing.T) {
 	expected := "-28438"
-	actual, err := FakeData("integer", "int4", "test", nil)
+	actual, err := FakeData(fakeColumn("integer", "int4"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(integer, int4)"`)
@@ -77,7 +89,7 @@ func TestInteger(t *testing.T) {
 
 func TestNumeric(t *testing.T) {
 	expected := "0811322671379150800157326235129945846821868512082537553695051971385284931761246231929013318345066849917934051195957005010251086127251253247586544397624525817539412331020434533385244205839290898122610708792367424668706785602363133320999798373918937192620122973904883210291017769830401549207192956615190582268764524782270053256398776771672152780608916891641197080442825486207178932425244851564734834984997608521734254687764242389127017711273996171340978533251579592924160545247932962012446230696058879773088763428272479238155885078944564082788458188430811113613818887378747863246531003871981073123085604417132219541113622202359094793505964425435060140804042966939739556761181864615569083045878909613100980618970189976712351459862055050461852410033003975448020666611730725960230218276123471670335554280490580672785225578363487366563112462792908834308281373772630585981225050222100393128762580847975949664061258788319913373775111260675684962953422609197029669144739593584421797864202355541581665875606117887864451984880804058947095035526888879126498479253710627995574347430850941507795714438026782504794660897714764065681262408427994558177621909508050642133368891769196134.87992980890452687254496530281313452418308345021556333328844056423075080503914600194182954116175192500973757212788055885878554177881573616281112314068453399355132608721902110751028326865857195352021920627063893774272105015640408231004126336166067368266170432356875949095918729330061322837976048643355377987347752485445937801199834615023850725496472663116326787692475932028853752084446222839821959831085052904942698178663649074776276298916702589849108030601435167976546227245223824652648480063672691841140208329324165280831672559268430900064213466316753366720010921992516265579935933807071036024447911179919236736188738536894079802716577648912780779843475591402407594663968330052276661265037781163882391143569566432364609581498810272195155009602718117577770537440451654376437961825958378189595014180064249539131383764526015179968661663911853251711366380073449666126951630357938227997176691469452842270548683727452866126429493220182116033086781796361447098320428324684146136960681826331250034160375544085503358349063914014999081614432871129551137387177659735463684332702551331202052186789176468625828452764878380113607044631326514479201033906110403724314335036748880736278178661364966674853199402263699151738826215930031067256420764732076202757150197405565632507533321973738817684412927257993684883597789783081723700826422795880358219850280172379023689421358267314951340406962754151194627892248864207035979445571689389893633771219546772674079697396401868269194535073272288076483773344562041892500972543121344710199139334924819157049167196446641282737591616825524467679375935855206664951500979330582224769914929395493719105239778437739773539928993785611978977080573585720446591374988877866859425678899823402187048584913024519630570737461927925698480967934196518367422192188237220497546795359871896492952209137646412773319685205490649833244204108611457350128404816483622851463976475276086630563802309761158009662717535110150798447337692322861134380852269898575322195498562814722437274412857643734864028472961746534644140697056264749133339905947992944012703061546102882064270400407857987655683346950518651495252836564781646394841820944574496860695411739242457590631661661547882409766760215648912956172546926759657441570579545260380680939886175013544496371294972932973316727970103458967261179330836019509528792136757989150787883495359047589484011175868195549046335239158328898502795916121560547264832009402402554538838328072504796043368737999708978613816726229375589928651026544851407120524127569532239439569541111923050373306385611422806311984802949522678474824139020702705618755062400723538490630278613321321248173984131778004934037284652818109921507965428578478294179794295290802095134495955561361263155688989612738480822907710367986437375620747733815346501240347525275096711211673226177412836742103075695765188968360031211526512449151646019612472253021661922726016441417651380020924093669082251497666253891755614617166877668644615675222697849336886463974562742834157737744908426889577868746289771202026758292426680598757880018309858801381330961195995639481883408539745478023248269127637914653590716485573751086011213572767004091616218020075699715894498909652159040668906731163211735270470041610646532969661810174903919638974043806125409784342186903782468461221315530063715488970333289193040661948673687731637827436533966326937687370895162775013966360755231399556294857995120560549478670281421455223060384796398890936287306146599329091748277434694705979028840496943856720166176073972535359324096527285804041959642726142398402367324313775604814904705392033018696269388942421528447982687700299483980172429118791197041091738000185262973878975054671923688495871852215479968030372221069913776927454180536311459848892985236130577211707915003414868655291401706012563162775643944282198862662712203864043955232248344713120535936431498737347099429355436765418379656929571300809451488537991373675074347585119593561316716789917184277971243673401882997660985385891303932402262651724240882896890190129713545936971566751629306718402842946616956373107713909904273014304620871852365138497264367483013409913300055921644088221548191066857413318010318777026151869045348830558144803613949522957875509715489598963684836071802926371536936965649446547838020979558356455330160231208151240193213760801490063461527436557847623903438505637895621099217139827745513799234386017683489118472138182434479499147278787599564000329534917377960473995169650848181752692088135726544193653408627237649466739629556459897440184226370110346123463165477129384727545310920237483762494040825160540138745180564578738844479523027598231096209078179330174507814420095659842868585659211643649810573231936376710035680370679700159666241700179361955272619948766148099405166149056054896418052819759318327293220423761518288989493356570742427116068890597576722619546529974317922650878328221322279169804158976560977284132291535699072193502199306992087022023725391295221317099229619124887663955304063509554000874356984955994967300635900946216915508274694032931971385578508090212254115711154651556557938861455183629520166807525517360116727207962789541977883636363052739090954907315606517666083348188219199180032481085469293513083417195064522542135250938199742196744871558011247548696431501559508883491853695934006557871676593932172481892472939452502500868163104373878178356241927136390965335488555471398007713390352358517555835886478962177048599332798130977254243107604918257895016295613875937478991054613977193667996777707636356121532119996832022225382481327474263037075324562297107857651739270902050843230904059839126365896774745304461783422209500083281101190640601784898621551638607491956566500092751786335265012207000573661805704937917212531690338798024083238073460356465865072789319129369000990885067571814389253346385785989360299992178987230534273270430633322960364416204858899529828036608241746532484043879023972331608612622699679252965473987442069084845874959730613267361376435463383306745727295658410885637442086950346592210715354549901582080838198257854287789101495305424388067005575668196832081654725527976335536486574635214332077403670868957585641046939223405061472427294905690628796510931009629569588961007047965162993220478638153795278081608845769168079824088503808244852662740970731368882057783991357069019205027359642509239738047670163332752723946697636636342187768530103807303613333553685034648357772521840809436293646781914071498647117274321192185589853606754370259291314429488325077036650837588376554485374509450590364918906578054057616651910448913413403176659998931171572795711627945508697646844843432637332022579395491496978972168388210871560652282659036966730951238031668851407105115426293677643629982582486828026797997077581572001189076104395370004577916669973184686055910684326701751570731664659473334401921382722512191378853369744548668750927769196705326232823322287256204430902344501446485159904397788144089041689260131755708740895902356910544626110408997764950442548170008000886150555273146394797497934428697161153578732643969177034837341453786644487456393281331455710950987309398393822009033682034453963255037346513394938564316433746458482830021259189253"
-	actual, err := FakeData("numeric", "numeric", "test", nil)
+	actual, err := FakeData(fakeColumn("numeric", "numeric"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(numeric, numeric)"`)
@@ -88,7 +100,7 @@ func TestNumeric(t *testing.T) {
 
 func TestReal(t *testing.T) {
 	expected := "0.232006"
-	actual, err := FakeData("real", "float4", "test", nil)
+	actual, err := FakeData(fakeColumn("real", "float4"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(real, float4)"`)
@@ -99,7 +111,7 @@ func TestReal(t *testing.T) {
 
 func TestSmallSerial(t *testing.T) {
 	expected := "4083"
-	actual, err := FakeData("smallint", "int2", "test", nil)
+	actual, err := FakeData(fakeColumn("smallint", "int2"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(smallint, int2)"`)
@@ -109,8 +121,8 @@ func TestSmallSerial(t *testing.T) {
 }
 
 func TestText(t *testing.T) {
-	expected := "'I.e..'"
-	actual, err := FakeData("text", "text", "test", nil)
+	expected := "I.e.."
+	actual, err := FakeData(fakeColumn("text", "text"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(text, text)"`)
@@ -120,8 +132,8 @@ func TestText(t *testing.T) {
 }
 
 func TestTimestampWith(t *testing.T) {
-	expected := "'2002-04-30T04:07:15Z'"
-	actual, err := FakeData("timestamp with time zone", "timestamptz", "test", nil)
+	expected := "2002-04-30T04:07:15Z"
+	actual, err := FakeData(fakeColumn("timestamp with time zone", "timestamptz"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(timestamp with time zone, timestamptz)"`)
@@ -131,8 +143,8 @@ func TestTimestampWith(t *testing.T) {
 }
 
 func TestTimestampWithoutTimeZone(t *testing.T) {
-	expected := "'1941-05-29'"
-	actual, err := FakeData("timestamp without time zone", "timestamp", "test", nil)
+	expected := "1941-05-29"
+	actual, err := FakeData(fakeColumn("timestamp without time zone", "timestamp"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(timestamp without time zone, timestamp)"`)
@@ -142,8 +154,8 @@ func TestTimestampWithoutTimeZone(t *testing.T) {
 }
 
 func TestUuid(t *testing.T) {
-	expected := "'a25df98e-e09a-4310-9cbc-b15bffe95e78'"
-	actual, err := FakeData("uuid", "uuid", "test", nil)
+	expected := "a25df98e-e09a-4310-9cbc-b15bffe95e78"
+	actual, err := FakeData(fakeColumn("uuid", "uuid"), nil)
 
 	if err != nil {
 		t.Errorf(`Error calling "FakeData(uuid, uuid)"`)
@@ -151,3 +163,760 @@ func TestUuid(t *testing.T) {
 
 	compare(t, actual, expected)
 }
+
+func TestEnum(t *testing.T) {
+	col := fakeColumn("enum", "enum('draft','published')")
+	col.EnumValues = []string{"draft", "published"}
+
+	actual, err := FakeData(col, nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(enum, enum('draft','published'))"`)
+	}
+
+	if actual != "draft" && actual != "published" {
+		t.Errorf("\nExpected one of the enum values\n\nGot:\n%s", actual)
+	}
+}
+
+func TestUserDefinedEnum(t *testing.T) {
+	col := fakeColumn("USER-DEFINED", "ticket_status")
+	col.EnumValues = []string{"open", "closed", "won't fix"}
+	options := Options{Weights: map[string]float64{"open": 0, "closed": 0}}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(USER-DEFINED, ticket_status)"`)
+		}
+
+		// Only the unweighted value can be picked
+		compare(t, actual, "won't fix")
+	}
+}
+
+func TestUserDefinedWithoutLabels(t *testing.T) {
+	_, err := FakeData(fakeColumn("USER-DEFINED", "geometry"), nil)
+
+	if err == nil {
+		t.Errorf(`Expected an error calling "FakeData(USER-DEFINED, geometry)"`)
+	}
+}
+
+func TestIntegerArray(t *testing.T) {
+	options := Options{Array: &ArrayOptions{MinLength: 3, MaxLength: 3}}
+	actual, err := FakeData(fakeColumn("ARRAY", "_int4"), &options)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(ARRAY, _int4)"`)
+	}
+
+	array, ok := actual.(Array)
+	if !ok || array.ElementType != "int4" || !regexp.MustCompile(`^\{-?\d+,-?\d+,-?\d+\}$`).MatchString(Text(array)) {
+		t.Errorf("\nExpected an int4 array of 3 elements\n\nGot:\n%v", actual)
+	}
+}
+
+func TestEmptyArray(t *testing.T) {
+	options := Options{Array: &ArrayOptions{MinLength: 0, MaxLength: 0}}
+	actual, err := FakeData(fakeColumn("ARRAY", "_timestamptz"), &options)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(ARRAY, _timestamptz)"`)
+	}
+
+	compare(t, actual, "{}")
+}
+
+func TestMultidimensionalArray(t *testing.T) {
+	col := fakeColumn("ARRAY", "_bool")
+	col.ArrayDimensions = 2
+	options := Options{Array: &ArrayOptions{MinLength: 2, MaxLength: 2, NullRatio: 1}}
+	actual, err := FakeData(col, &options)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(ARRAY, _bool)"`)
+	}
+
+	compare(t, actual, "{{NULL,NULL},{NULL,NULL}}")
+}
+
+func TestEnumArray(t *testing.T) {
+	col := fakeColumn("ARRAY", "_Ticket Status")
+	col.UdtSchema = "support"
+	col.EnumValues = []string{"open"}
+	options := Options{Array: &ArrayOptions{MinLength: 1, MaxLength: 1}}
+	actual, err := FakeData(col, &options)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(ARRAY, _Ticket Status)"`)
+	}
+
+	if array, ok := actual.(Array); !ok || array.ElementType != `support."Ticket Status"` {
+		t.Errorf("\nExpected an array of support.\"Ticket Status\"\n\nGot:\n%v", actual)
+	}
+
+	compare(t, actual, `{"open"}`)
+}
+
+func TestUnknownArray(t *testing.T) {
+	_, err := FakeData(fakeColumn("ARRAY", "_geometry"), nil)
+
+	if err == nil {
+		t.Errorf(`Expected an error calling "FakeData(ARRAY, _geometry)"`)
+	}
+}
+
+func TestBoundedInteger(t *testing.T) {
+	options := Options{Min: &Bound{Value: 0, Exclusive: true}, Max: &Bound{Value: 3}}
+
+	for range 100 {
+		actual, err := FakeData(fakeColumn("integer", "int4"), &options)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(integer, int4)"`)
+		}
+
+		if actual != int64(1) && actual != int64(2) && actual != int64(3) {
+			t.Fatalf("\nExpected a value from 1 to 3\n\nGot:\n%v", actual)
+		}
+	}
+}
+
+func TestBoundedNumeric(t *testing.T) {
+	col := fakeColumn("numeric", "numeric")
+	col.NumericPrecision = sql.NullInt32{Int32: 4, Valid: true}
+	col.NumericScale = sql.NullInt32{Int32: 2, Valid: true}
+	options := Options{Min: &Bound{Value: -0.5}}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(numeric(4,2), numeric)"`)
+		}
+
+		n, _ := strconv.ParseFloat(Text(actual), 64)
+		if !regexp.MustCompile(`^-?\d+\.\d{2}$`).MatchString(Text(actual)) || n < -0.5 || n > 99.99 {
+			t.Fatalf("\nExpected a numeric(4,2) value of at least -0.5\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestBoundedWithoutValues(t *testing.T) {
+	options := Options{Min: &Bound{Value: 200}}
+	_, err := FakeData(fakeColumn("tinyint", "tinyint"), &options)
+
+	if err == nil {
+		t.Errorf(`Expected an error calling "FakeData(tinyint, tinyint)" above 200`)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	col := fakeColumn("character varying", "varchar")
+	col.CharacterMaximumLength = sql.NullInt32{Int32: 16, Valid: true}
+
+	actual, ok := Distinct(col, nil, "jo.smith@mail.io", 42)
+	if !ok || actual != "jo.smi42@mail.io" {
+		t.Errorf("Expected:\n%s\n\nGot:\n%v", "jo.smi42@mail.io", actual)
+	}
+
+	options := Options{Min: &Bound{Value: 0, Exclusive: true}, Max: &Bound{Value: 3}}
+	actual, ok = Distinct(fakeColumn("integer", "int4"), &options, int64(2), 2)
+	if !ok || actual != int64(3) {
+		t.Errorf("Expected:\n%d\n\nGot:\n%v", 3, actual)
+	}
+
+	if _, ok = Distinct(fakeColumn("integer", "int4"), &options, int64(2), 3); ok {
+		t.Errorf("Expected no value past the end of the range")
+	}
+}
+
+func TestTextLength(t *testing.T) {
+	col := fakeColumn("text", "text")
+	options := Options{MinLength: 30, MaxLength: 40}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(text, text)"`)
+		}
+
+		length := len([]rune(Text(actual)))
+		if length < 30 || length > 40 {
+			t.Fatalf("\nExpected 30 to 40 characters\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestNumericPrecisionScale(t *testing.T) {
+	col := fakeColumn("numeric", "numeric")
+	col.NumericPrecision = sql.NullInt32{Int32: 5, Valid: true}
+	col.NumericScale = sql.NullInt32{Int32: 2, Valid: true}
+
+	for range 100 {
+		actual, err := FakeData(col, nil)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(numeric(5,2), numeric)"`)
+		}
+
+		if !regexp.MustCompile(`^\d{1,3}\.\d{2}$`).MatchString(Text(actual)) {
+			t.Fatalf("\nExpected a numeric(5,2) value\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestNumericScaleOnly(t *testing.T) {
+	col := fakeColumn("numeric", "numeric")
+	col.NumericPrecision = sql.NullInt32{Int32: 3, Valid: true}
+	col.NumericScale = sql.NullInt32{Int32: 3, Valid: true}
+
+	actual, err := FakeData(col, nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(numeric(3,3), numeric)"`)
+	}
+
+	if !regexp.MustCompile(`^0\.\d{3}$`).MatchString(Text(actual)) {
+		t.Errorf("\nExpected a numeric(3,3) value\n\nGot:\n%s", actual)
+	}
+}
+
+func TestCharacterVarying(t *testing.T) {
+	col := fakeColumn("character varying", "varchar")
+	col.CharacterMaximumLength = sql.NullInt32{Int32: 3, Valid: true}
+	options := Options{Generator: "company"}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Errorf(`Error calling "FakeData(character varying(3), varchar)"`)
+		}
+
+		if len([]rune(Text(actual))) > 3 {
+			t.Fatalf("\nExpected at most 3 characters\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestBitLength(t *testing.T) {
+	col := fakeColumn("bit", "bit")
+	col.CharacterMaximumLength = sql.NullInt32{Int32: 3, Valid: true}
+
+	actual, err := FakeData(col, nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(bit(3), bit)"`)
+	}
+
+	if _, ok := actual.(Bits); !ok || !regexp.MustCompile(`^[01]{3}$`).MatchString(Text(actual)) {
+		t.Errorf("\nExpected a bit(3) value\n\nGot:\n%s", actual)
+	}
+}
+
+func matches(t *testing.T, actual Value, pattern string) {
+	if !regexp.MustCompile(pattern).MatchString(Text(actual)) {
+		t.Errorf("\nExpected a match for:\n%s\n\nGot:\n%s", pattern, Text(actual))
+	}
+}
+
+func TestBitVarying(t *testing.T) {
+	actual, err := FakeData(fakeColumn("bit varying", "varbit"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(bit varying, varbit)"`)
+	}
+
+	matches(t, actual, `^[01]{1,8}$`)
+}
+
+func TestBox(t *testing.T) {
+	actual, err := FakeData(fakeColumn("box", "box"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(box, box)"`)
+	}
+
+	matches(t, actual, `^\(\(-?\d+\.\d{2},-?\d+\.\d{2}\),\(-?\d+\.\d{2},-?\d+\.\d{2}\)\)$`)
+}
+
+func TestBytea(t *testing.T) {
+	actual, err := FakeData(fakeColumn("bytea", "bytea"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(bytea, bytea)"`)
+	}
+
+	matches(t, actual, `^\\x([0-9a-f]{2})+$`)
+}
+
+func TestCharacter(t *testing.T) {
+	actual, err := FakeData(fakeColumn("character", "bpchar"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(character, bpchar)"`)
+	}
+
+	matches(t, actual, `^.+$`)
+}
+
+func TestCidr(t *testing.T) {
+	actual, err := FakeData(fakeColumn("cidr", "cidr"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(cidr, cidr)"`)
+	}
+
+	matches(t, actual, `^\d+\.\d+\.\d+\.0/24$`)
+}
+
+func TestCircle(t *testing.T) {
+	actual, err := FakeData(fakeColumn("circle", "circle"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(circle, circle)"`)
+	}
+
+	matches(t, actual, `^<\(-?\d+\.\d{2},-?\d+\.\d{2}\),\d+\.\d{2}>$`)
+}
+
+func TestDate(t *testing.T) {
+	actual, err := FakeData(fakeColumn("date", "date"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(date, date)"`)
+	}
+
+	matches(t, actual, `^\d{4}-\d{2}-\d{2}$`)
+}
+
+func TestDateRange(t *testing.T) {
+	actual, err := FakeData(fakeColumn("daterange", "daterange"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(daterange, daterange)"`)
+	}
+
+	matches(t, actual, `^\[\d{4}-\d{2}-\d{2},\d{4}-\d{2}-\d{2}\)$`)
+}
+
+func TestInet(t *testing.T) {
+	actual, err := FakeData(fakeColumn("inet", "inet"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(inet, inet)"`)
+	}
+
+	matches(t, actual, `^\d+\.\d+\.\d+\.\d+$`)
+}
+
+func TestInt4Range(t *testing.T) {
+	actual, err := FakeData(fakeColumn("int4range", "int4range"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(int4range, int4range)"`)
+	}
+
+	matches(t, actual, `^\[-?\d+,-?\d+\)$`)
+}
+
+func TestInterval(t *testing.T) {
+	actual, err := FakeData(fakeColumn("interval", "interval"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(interval, interval)"`)
+	}
+
+	matches(t, actual, `^\d+ days \d{2}:\d{2}:\d{2}$`)
+}
+
+func TestLine(t *testing.T) {
+	actual, err := FakeData(fakeColumn("line", "line"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(line, line)"`)
+	}
+
+	matches(t, actual, `^\{\d+\.\d{2},-?\d+\.\d{2},-?\d+\.\d{2}\}$`)
+}
+
+func TestLseg(t *testing.T) {
+	actual, err := FakeData(fakeColumn("lseg", "lseg"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(lseg, lseg)"`)
+	}
+
+	matches(t, actual, `^\[\(-?\d+\.\d{2},-?\d+\.\d{2}\),\(-?\d+\.\d{2},-?\d+\.\d{2}\)\]$`)
+}
+
+func TestMacaddr(t *testing.T) {
+	actual, err := FakeData(fakeColumn("macaddr", "macaddr"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(macaddr, macaddr)"`)
+	}
+
+	matches(t, actual, `^([0-9a-f]{2}:){5}[0-9a-f]{2}$`)
+}
+
+func TestMacaddr8(t *testing.T) {
+	actual, err := FakeData(fakeColumn("macaddr8", "macaddr8"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(macaddr8, macaddr8)"`)
+	}
+
+	matches(t, actual, `^([0-9a-f]{2}:){7}[0-9a-f]{2}$`)
+}
+
+func TestMoney(t *testing.T) {
+	actual, err := FakeData(fakeColumn("money", "money"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(money, money)"`)
+	}
+
+	matches(t, actual, `^\d+\.\d{2}$`)
+}
+
+func TestName(t *testing.T) {
+	actual, err := FakeData(fakeColumn("name", "name"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(name, name)"`)
+	}
+
+	matches(t, actual, `^.{1,63}$`)
+}
+
+func TestNumRange(t *testing.T) {
+	actual, err := FakeData(fakeColumn("numrange", "numrange"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(numrange, numrange)"`)
+	}
+
+	matches(t, actual, `^\[-?\d+\.\d{2},-?\d+\.\d{2}\)$`)
+}
+
+func TestOid(t *testing.T) {
+	actual, err := FakeData(fakeColumn("oid", "oid"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(oid, oid)"`)
+	}
+
+	matches(t, actual, `^\d+$`)
+}
+
+func TestPath(t *testing.T) {
+	actual, err := FakeData(fakeColumn("path", "path"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(path, path)"`)
+	}
+
+	matches(t, actual, `^\[(\(-?\d+\.\d{2},-?\d+\.\d{2}\),?){2,5}\]$`)
+}
+
+func TestPgLsn(t *testing.T) {
+	actual, err := FakeData(fakeColumn("pg_lsn", "pg_lsn"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(pg_lsn, pg_lsn)"`)
+	}
+
+	matches(t, actual, `^[0-9A-F]+/[0-9A-F]+$`)
+}
+
+func TestPoint(t *testing.T) {
+	actual, err := FakeData(fakeColumn("point", "point"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(point, point)"`)
+	}
+
+	matches(t, actual, `^\(-?\d+\.\d{2},-?\d+\.\d{2}\)$`)
+}
+
+func TestPolygon(t *testing.T) {
+	actual, err := FakeData(fakeColumn("polygon", "polygon"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(polygon, polygon)"`)
+	}
+
+	matches(t, actual, `^\((\(-?\d+\.\d{2},-?\d+\.\d{2}\),?){3,6}\)$`)
+}
+
+func TestTime(t *testing.T) {
+	actual, err := FakeData(fakeColumn("time without time zone", "time"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(time without time zone, time)"`)
+	}
+
+	matches(t, actual, `^\d{2}:\d{2}:\d{2}$`)
+}
+
+func TestTimeWithTimeZone(t *testing.T) {
+	actual, err := FakeData(fakeColumn("time with time zone", "timetz"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(time with time zone, timetz)"`)
+	}
+
+	matches(t, actual, `^\d{2}:\d{2}:\d{2}\+00$`)
+}
+
+func TestTsRange(t *testing.T) {
+	actual, err := FakeData(fakeColumn("tsrange", "tsrange"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(tsrange, tsrange)"`)
+	}
+
+	matches(t, actual, `^\[\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2},\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\)$`)
+}
+
+func TestTsQuery(t *testing.T) {
+	actual, err := FakeData(fakeColumn("tsquery", "tsquery"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(tsquery, tsquery)"`)
+	}
+
+	matches(t, actual, `^[a-z]+ & [a-z]+$`)
+}
+
+func TestTsVector(t *testing.T) {
+	actual, err := FakeData(fakeColumn("tsvector", "tsvector"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(tsvector, tsvector)"`)
+	}
+
+	matches(t, actual, `^[a-z]+( [a-z]+)*$`)
+}
+
+func TestXml(t *testing.T) {
+	actual, err := FakeData(fakeColumn("xml", "xml"), nil)
+
+	if err != nil {
+		t.Errorf(`Error calling "FakeData(xml, xml)"`)
+	}
+
+	matches(t, actual, `^<note><from>[^<]+</from><body>[^<]+</body></note>$`)
+}
+
+func TestBoundedDate(t *testing.T) {
+	col := fakeColumn("date", "date")
+	low, _ := ParseBound(col, "-90d")
+	high, _ := ParseBound(col, "now")
+	options := Options{
+		Min:          &Bound{Value: low},
+		Max:          &Bound{Value: high},
+		Distribution: &Distribution{Type: DistributionExponential, TowardMax: true},
+	}
+
+	recent := 0
+	for range 1000 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(date, date)"`)
+		}
+
+		if Text(actual) < "2025-01-12" || Text(actual) > "2025-04-12" {
+			t.Fatalf("\nExpected a date within the last 90 days\n\nGot:\n%s", actual)
+		}
+
+		if Text(actual) >= "2025-03-13" {
+			recent++
+		}
+	}
+
+	if recent < 700 {
+		t.Errorf("\nExpected most dates within the last 30 days\n\nGot:\n%d of 1000", recent)
+	}
+}
+
+func TestBoundedTimestamp(t *testing.T) {
+	col := fakeColumn("timestamp with time zone", "timestamptz")
+	low, _ := ParseBound(col, "2025-04-01")
+	high, _ := ParseBound(col, "2025-04-01T12:00:00Z")
+	options := Options{Min: &Bound{Value: low}, Max: &Bound{Value: high}}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(timestamp with time zone, timestamptz)"`)
+		}
+
+		if !strings.HasPrefix(Text(actual), "2025-04-01T") || Text(actual) > "2025-04-01T12:00:00Z" {
+			t.Fatalf("\nExpected a timestamp on the morning of 2025-04-01\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestNormalDistribution(t *testing.T) {
+	mean := 50.0
+	options := Options{
+		Min:          &Bound{Value: 0},
+		Max:          &Bound{Value: 100},
+		Distribution: &Distribution{Type: DistributionNormal, Mean: &mean, StdDev: 5},
+	}
+
+	near := 0
+	for range 1000 {
+		actual, err := FakeData(fakeColumn("double precision", "float8"), &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(double precision, float8)"`)
+		}
+
+		n, _ := strconv.ParseFloat(Text(actual), 64)
+		if n < 0 || n > 100 {
+			t.Fatalf("\nExpected a value from 0 to 100\n\nGot:\n%s", actual)
+		}
+
+		if n >= 40 && n <= 60 {
+			near++
+		}
+	}
+
+	if near < 900 {
+		t.Errorf("\nExpected most values within two standard deviations of the mean\n\nGot:\n%d of 1000", near)
+	}
+}
+
+func TestZipfDistribution(t *testing.T) {
+	options := Options{
+		Min:          &Bound{Value: 1},
+		Max:          &Bound{Value: 1000},
+		Distribution: &Distribution{Type: DistributionZipf},
+	}
+
+	counts := make(map[Value]int)
+	for range 1000 {
+		actual, err := FakeData(fakeColumn("integer", "int4"), &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(integer, int4)"`)
+		}
+
+		counts[actual]++
+	}
+
+	if counts[int64(1)] < 500 || counts[int64(1)] < counts[int64(2)] {
+		t.Errorf("\nExpected 1 to be picked most\n\nGot:\n%v", counts)
+	}
+}
+
+func TestBucketDistribution(t *testing.T) {
+	col := fakeColumn("numeric", "numeric")
+	options := Options{
+		Min: &Bound{Value: 0},
+		Max: &Bound{Value: 1000},
+		Distribution: &Distribution{Type: DistributionBuckets, Buckets: []Bucket{
+			{Min: 0, Max: 10, Weight: 9},
+			{Min: 500, Max: 1000, Weight: 1},
+		}},
+	}
+
+	cheap := 0
+	for range 1000 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(numeric, numeric)"`)
+		}
+
+		n, _ := strconv.ParseFloat(Text(actual), 64)
+		if n > 10 && n < 500 {
+			t.Fatalf("\nExpected a value within one of the buckets\n\nGot:\n%s", actual)
+		}
+
+		if n <= 10 {
+			cheap++
+		}
+	}
+
+	if cheap < 850 || cheap > 950 {
+		t.Errorf("\nExpected about 900 values in the first bucket\n\nGot:\n%d of 1000", cheap)
+	}
+}
+
+func TestParseBound(t *testing.T) {
+	col := fakeColumn("timestamp with time zone", "timestamptz")
+	for text, expected := range map[string]string{
+		"now":                  "2025-04-12T10:00:00Z",
+		"-90d":                 "2025-01-12T10:00:00Z",
+		"+2w":                  "2025-04-26T10:00:00Z",
+		"2024-01-31":           "2024-01-31T00:00:00Z",
+		"2024-01-31 08:30:00":  "2024-01-31T08:30:00Z",
+		"2024-01-31T08:30:00Z": "2024-01-31T08:30:00Z",
+	} {
+		n, err := ParseBound(col, text)
+		if err != nil {
+			t.Errorf(`Error calling "ParseBound(%s)": %s`, text, err)
+		}
+
+		compare(t, formatMoment(col, time.Unix(int64(n), 0).UTC()), expected)
+	}
+
+	_, err := ParseBound(col, "90 days ago")
+	if err == nil {
+		t.Errorf("Expected an error reading a time that isn't supported")
+	}
+
+	_, err = ParseBound(fakeColumn("text", "text"), "1")
+	if err == nil {
+		t.Errorf("Expected an error bounding a text column")
+	}
+}
+
+func TestBoundedTimestampWithinDay(t *testing.T) {
+	col := fakeColumn("timestamp without time zone", "timestamp")
+	low, _ := ParseBound(col, "-6h")
+	high, _ := ParseBound(col, "now")
+	options := Options{Min: &Bound{Value: low}, Max: &Bound{Value: high}}
+
+	for range 100 {
+		actual, err := FakeData(col, &options)
+
+		if err != nil {
+			t.Fatalf(`Error calling "FakeData(timestamp without time zone, timestamp)"`)
+		}
+
+		if Text(actual) < "2025-04-12 04:00:00" || Text(actual) > "2025-04-12 10:00:00" {
+			t.Fatalf("\nExpected a timestamp within the last 6 hours\n\nGot:\n%s", actual)
+		}
+	}
+}
+
+func TestMySQLTimestamp(t *testing.T) {
+	col := fakeColumn("timestamp", "timestamp")
+	low, _ := ParseBound(col, "1900-01-01")
+	bounded := Options{Min: &Bound{Value: low}}
+
+	for _, options := range []*Options{nil, &bounded} {
+		for range 100 {
+			actual, err := FakeData(col, options)
+
+			if err != nil {
+				t.Fatalf(`Error calling "FakeData(timestamp, timestamp)"`)
+			}
+
+			if Text(actual) < "1970-01-02" || Text(actual) > "2025-04-12 10:00:00" {
+				t.Fatalf("\nExpected a timestamp from 1970 on\n\nGot:\n%s", actual)
+			}
+		}
+	}
+}
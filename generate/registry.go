@@ -0,0 +1,173 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
+)
+
+// Generator creates values for columns of the datatypes it's compatible with
+type Generator struct {
+	// Datatypes lists the datatypes of the columns the generator can be
+	// used on
+	Datatypes []string
+
+	// Params lists the names of the parameters the generator takes
+	Params []string
+
+	// Generate creates a value for a column with the given faker and
+	// parameters, which are nil when none were given
+	Generate func(f *gofakeit.Faker, col Column, params *gofakeit.MapParams) (Value, error)
+}
+
+// generators holds the registered generators, keyed by their lowercase name
+var generators = make(map[string]Generator)
+
+// textDatatypes are the datatypes of the columns that hold character strings
+var textDatatypes = []string{"text", "character varying", "character", "name"}
+
+// Register makes a generator available to columns by name. Names aren't case
+// sensitive and registering a name again replaces its generator.
+func Register(name string, generator Generator) {
+	generators[strings.ToLower(name)] = generator
+}
+
+// Generators returns the names of the registered generators in order
+func Generators() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ValidateGenerator checks that a column can use the named generator with the
+// given parameters, listing what it could use instead when it can't. The
+// elements of array columns are what the generator fills in. The parameters
+// are returned in the form the generator takes them.
+func ValidateGenerator(col Column, name string, params map[string]any) (*gofakeit.MapParams, error) {
+	datatype, err := generatorDatatype(col)
+	if err != nil {
+		return nil, err
+	}
+
+	var choices []string
+	for _, choice := range Generators() {
+		if slices.Contains(generators[choice].Datatypes, datatype) {
+			choices = append(choices, choice)
+		}
+	}
+
+	expected := "no generators support it"
+	if len(choices) > 0 {
+		expected = "expected one of: " + strings.Join(choices, ", ")
+	}
+
+	generator, ok := generators[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.New("Column '" + col.Name + "' has an unknown generator \"" + name + "\", " + expected)
+	}
+
+	if !slices.Contains(generator.Datatypes, datatype) {
+		return nil, errors.New("Column '" + col.Name + "' is a " + datatype + " column and cannot use the \"" + name + "\" generator, " + expected)
+	}
+
+	var mapParams *gofakeit.MapParams
+	for key, value := range params {
+		i := slices.IndexFunc(generator.Params, func(param string) bool { return strings.EqualFold(param, key) })
+		if i < 0 {
+			expected := "it takes no parameters"
+			if len(generator.Params) > 0 {
+				expected = "expected one of: " + strings.Join(generator.Params, ", ")
+			}
+
+			return nil, errors.New("Column '" + col.Name + "' has an unknown parameter \"" + key + "\" for the \"" + name + "\" generator, " + expected)
+		}
+
+		if mapParams == nil {
+			mapParams = gofakeit.NewMapParams()
+		}
+
+		for _, v := range paramValues(value) {
+			mapParams.Add(generator.Params[i], v)
+		}
+	}
+
+	// Try the generator out on a faker of its own so that bad parameters
+	// are caught up front without changing the values that get generated
+	faker := gofakeit.New(0)
+	value, err := generator.Generate(faker, col, mapParams)
+	if err != nil {
+		return nil, errors.New("Column '" + col.Name + "' cannot use the \"" + name + "\" generator: " + err.Error())
+	}
+
+	// Many generators make numbers wider than the smaller integer types
+	// can hold, so they're tried a while longer to see that they stay
+	// within the column
+	if limits, ok := integerLimits[datatype]; ok {
+		for range 1000 {
+			n, err := strconv.ParseInt(Text(value), 10, 64)
+			if err != nil || n < limits[0] || n > limits[1] {
+				return nil, errors.New("Column '" + col.Name + "' is a " + datatype + " column and cannot hold " + Text(value) + " from the \"" + name + "\" generator, expected values from " + strconv.FormatInt(limits[0], 10) + " to " + strconv.FormatInt(limits[1], 10))
+			}
+
+			value, err = generator.Generate(faker, col, mapParams)
+			if err != nil {
+				return nil, errors.New("Column '" + col.Name + "' cannot use the \"" + name + "\" generator: " + err.Error())
+			}
+		}
+	}
+
+	return mapParams, nil
+}
+
+// paramValues converts a parameter from the config into the strings
+// generators take. Lists give a string for each of their items and objects
+// are given as JSON.
+func paramValues(value any) []string {
+	switch value := value.(type) {
+	case []any:
+		var values []string
+		for _, item := range value {
+			values = append(values, paramValues(item)...)
+		}
+
+		return values
+	case map[string]any:
+		encoded, _ := json.Marshal(value)
+		return []string{string(encoded)}
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// runGenerator fills in a column with the named generator
+func runGenerator(col Column, name string, params *gofakeit.MapParams) (Value, error) {
+	generator, ok := generators[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.New("Unknown generator \"" + name + "\"")
+	}
+
+	return generator.Generate(gofakeit.GlobalFaker, col, params)
+}
+
+// generatorDatatype returns the datatype a generator needs to support to be
+// used on a column
+func generatorDatatype(col Column) (string, error) {
+	if col.DataType == "ARRAY" {
+		return udtToPsqlDatatype(col)
+	}
+
+	return col.DataType, nil
+}
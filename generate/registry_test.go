@@ -0,0 +1,176 @@
+package generate
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
+)
+
+func TestGenerator(t *testing.T) {
+	col := fakeColumn("character varying", "varchar")
+	col.CharacterMaximumLength = sql.NullInt32{Int32: 5, Valid: true}
+
+	actual, err := FakeData(col, &Options{Generator: "email"})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	// Generated values still have to fit in the column
+	if len(Text(actual)) != 5 {
+		t.Errorf("Expected an email cut down to 5 characters, got %q", Text(actual))
+	}
+
+	actual, err = FakeData(fakeColumn("inet", "inet"), &Options{Generator: "ipv4"})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	matches(t, actual, `^\d+\.\d+\.\d+\.\d+$`)
+}
+
+func TestArrayGenerator(t *testing.T) {
+	actual, err := FakeData(fakeColumn("ARRAY", "_uuid"), &Options{Generator: "uuid"})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	for _, element := range actual.(Array).Elements {
+		matches(t, element, `^[0-9a-f-]{36}$`)
+	}
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	Register("Constant", Generator{
+		Datatypes: []string{"integer"},
+		Generate: func(f *gofakeit.Faker, col Column, params *gofakeit.MapParams) (Value, error) {
+			return int64(7), nil
+		},
+	})
+	defer delete(generators, "constant")
+
+	_, err := ValidateGenerator(fakeColumn("integer", "int4"), "constant", nil)
+	if err != nil {
+		t.Fatalf("Error calling ValidateGenerator: %s", err)
+	}
+
+	actual, err := FakeData(fakeColumn("integer", "int4"), &Options{Generator: "constant"})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	compare(t, actual, "7")
+}
+
+func TestValidateGenerator(t *testing.T) {
+	_, err := ValidateGenerator(fakeColumn("box", "box"), "uuid", nil)
+	if err == nil || !strings.HasSuffix(err.Error(), "no generators support it") {
+		t.Errorf("Expected an error using a text generator on a box column, got %v", err)
+	}
+
+	_, err = ValidateGenerator(fakeColumn("uuid", "uuid"), "nope", nil)
+	expected := `Column 'test' has an unknown generator "nope", expected one of: uuid`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%v", expected, err)
+	}
+}
+
+func TestGeneratorParams(t *testing.T) {
+	col := fakeColumn("text", "text")
+	params, err := ValidateGenerator(col, "sentence", map[string]any{"wordCount": 8})
+	if err != nil {
+		t.Fatalf("Error calling ValidateGenerator: %s", err)
+	}
+
+	actual, err := FakeData(col, &Options{Generator: "sentence", GeneratorParams: params})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	if len(strings.Fields(Text(actual))) != 8 {
+		t.Errorf("Expected a sentence of 8 words, got %q", Text(actual))
+	}
+
+	_, err = ValidateGenerator(col, "sentence", map[string]any{"words": 8})
+	expected := `Column 'test' has an unknown parameter "words" for the "sentence" generator, expected one of: wordcount`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%v", expected, err)
+	}
+
+	_, err = ValidateGenerator(col, "sentence", map[string]any{"wordCount": "many"})
+	if err == nil {
+		t.Errorf("Expected an error passing text as the word count")
+	}
+}
+
+func TestCatalogGenerator(t *testing.T) {
+	col := fakeColumn("integer", "int4")
+	params, err := ValidateGenerator(col, "intRange", map[string]any{"min": 5, "max": 5})
+	if err != nil {
+		t.Fatalf("Error calling ValidateGenerator: %s", err)
+	}
+
+	actual, err := FakeData(col, &Options{Generator: "intrange", GeneratorParams: params})
+	if err != nil {
+		t.Fatalf("Error calling FakeData: %s", err)
+	}
+
+	compare(t, actual, "5")
+
+	_, err = ValidateGenerator(fakeColumn("date", "date"), "pastdate", nil)
+	if err != nil {
+		t.Errorf("Expected dates to be able to use pastdate, got %s", err)
+	}
+}
+
+func TestCatalogGeneratorLimits(t *testing.T) {
+	col := fakeColumn("smallint", "int2")
+	_, err := ValidateGenerator(col, "int32", nil)
+	if err == nil || !strings.Contains(err.Error(), "-32768 to 32767") {
+		t.Errorf("Expected an error for values a smallint can't hold, got %v", err)
+	}
+
+	_, err = ValidateGenerator(col, "number", nil)
+	if err == nil {
+		t.Errorf("Expected an error for number without bounds on a smallint")
+	}
+
+	for _, name := range []string{"year", "int8"} {
+		_, err = ValidateGenerator(col, name, nil)
+		if err != nil {
+			t.Errorf("Expected a smallint to be able to use %s, got %s", name, err)
+		}
+	}
+
+	_, err = ValidateGenerator(col, "number", map[string]any{"min": 1, "max": 100})
+	if err != nil {
+		t.Errorf("Expected a smallint to be able to use number from 1 to 100, got %s", err)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	col := fakeColumn("character varying", "varchar")
+	col.CharacterMaximumLength = sql.NullInt32{Int32: 8, Valid: true}
+
+	options := &Options{Regex: regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)}
+	for range 10 {
+		actual, err := FakeData(col, options)
+		if err != nil {
+			t.Fatalf("Error calling FakeData: %s", err)
+		}
+
+		matches(t, actual, `^[A-Z]{3}-\d{4}$`)
+	}
+
+	// Nothing matching fits in the column
+	col.CharacterMaximumLength.Int32 = 4
+	_, err := FakeData(col, options)
+	if err == nil {
+		t.Errorf("Expected an error when no value matching the pattern fits")
+	}
+}
@@ -0,0 +1,148 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/value"
+)
+
+// ParseTemplate reads the template a column's values are made from. Templates
+// are Go text/templates that can call any registered generator by name,
+// passing its parameters in order as in {{number 1000 9999}}, and refer to
+// the values of the other columns in the same row as in {{.first_name}}.
+// Referring to a column the table doesn't have is an error.
+func ParseTemplate(col Column, text string, columnNames []string) (*template.Template, error) {
+	tmpl, err := template.New(col.Name).Option("missingkey=error").Funcs(templateFuncs(gofakeit.GlobalFaker)).Parse(text)
+	if err != nil {
+		return nil, errors.New("Column '" + col.Name + "' has an invalid template: " + err.Error())
+	}
+
+	// Try the template out on a faker of its own so that mistakes are
+	// caught up front without changing the values that get generated
+	values := make(map[string]string)
+	for _, name := range columnNames {
+		values[name] = ""
+	}
+
+	trial, err := tmpl.Clone()
+	if err == nil {
+		err = trial.Funcs(templateFuncs(gofakeit.New(0))).Execute(new(strings.Builder), values)
+	}
+	if err != nil {
+		return nil, errors.New("Column '" + col.Name + "' has an invalid template: " + err.Error())
+	}
+
+	return tmpl, nil
+}
+
+// FromTemplate fills in a column from its template given the values of the
+// other columns in the row as text. The text is read as a number or boolean
+// for the columns that hold them, so it's written out the same way as the
+// values generated for them.
+func FromTemplate(col Column, options *Options, values map[string]string) (Value, error) {
+	var value strings.Builder
+	err := options.Template.Execute(&value, values)
+	if err != nil {
+		return nil, err
+	}
+
+	text := value.String()
+	if IsText(col.DataType) || col.DataType == "name" {
+		return fitText(col, options, text), nil
+	}
+
+	read, err := FromText(col, text)
+	if err != nil {
+		return nil, errors.New("Column '" + col.Name + "' has a template that gives a value it can't hold: " + err.Error())
+	}
+
+	return read, nil
+}
+
+// FromText reads a value given as text, like those read back from the
+// database, as a number or boolean for the columns that hold them so it's
+// written out the same way as the values generated for them. The values of
+// other columns are kept as text.
+func FromText(col Column, text string) (Value, error) {
+	switch {
+	case col.DataType == "boolean":
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, errors.New("Cannot read the value " + text + " as a boolean")
+		}
+
+		return b, nil
+	case IsNumeric(col.DataType):
+		if _, ok := integerRanges[col.DataType]; ok {
+			n, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, errors.New("Cannot read the value " + text + " as a whole number")
+			}
+
+			return n, nil
+		}
+
+		_, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errors.New("Cannot read the value " + text + " as a number")
+		}
+
+		return Number(text), nil
+	default:
+		return text, nil
+	}
+}
+
+// funcName matches the generator names templates can call, since they can
+// only call functions named like Go identifiers
+var funcName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// templateFuncs makes each registered generator a function templates can
+// call, along with a few for changing the case of text
+func templateFuncs(f *gofakeit.Faker) template.FuncMap {
+	funcs := template.FuncMap{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+
+	for name, generator := range generators {
+		if !funcName.MatchString(name) {
+			continue
+		}
+
+		funcs[name] = func(args ...any) (string, error) {
+			if len(args) > len(generator.Params) {
+				return "", errors.New("the \"" + name + "\" generator takes " + fmt.Sprint(len(generator.Params)) + " parameters")
+			}
+
+			var params *gofakeit.MapParams
+			for i, arg := range args {
+				if params == nil {
+					params = gofakeit.NewMapParams()
+				}
+
+				for _, v := range paramValues(arg) {
+					params.Add(generator.Params[i], v)
+				}
+			}
+
+			// Everything in a template ends up as text
+			value, err := generator.Generate(f, Column{Name: name, DataType: "text"}, params)
+			if err != nil {
+				return "", err
+			}
+
+			return Text(value), nil
+		}
+	}
+
+	return funcs
+}
@@ -0,0 +1,75 @@
+package generate
+
+import (
+	"testing"
+
+	. "dummy/sqldatabase/value"
+)
+
+func TestTemplate(t *testing.T) {
+	col := fakeColumn("text", "text")
+	tmpl, err := ParseTemplate(col, "ORD-{{intrange 1000 1000}}-{{upper .code}}", []string{"code", "test"})
+	if err != nil {
+		t.Fatalf("Error calling ParseTemplate: %s", err)
+	}
+
+	actual, err := FromTemplate(col, &Options{Template: tmpl}, map[string]string{"code": "ab", "test": ""})
+	if err != nil {
+		t.Fatalf("Error calling FromTemplate: %s", err)
+	}
+
+	compare(t, actual, "ORD-1000-AB")
+}
+
+func TestTemplateTypes(t *testing.T) {
+	cases := map[string]Value{
+		"integer": int64(42),
+		"numeric": Number("42"),
+		"boolean": true,
+	}
+
+	for datatype, expected := range cases {
+		col := fakeColumn(datatype, datatype)
+		tmpl, err := ParseTemplate(col, "{{.value}}", []string{"value"})
+		if err != nil {
+			t.Fatalf("Error calling ParseTemplate: %s", err)
+		}
+
+		value := "42"
+		if datatype == "boolean" {
+			value = "true"
+		}
+
+		actual, err := FromTemplate(col, &Options{Template: tmpl}, map[string]string{"value": value})
+		if err != nil {
+			t.Fatalf("Error calling FromTemplate: %s", err)
+		}
+
+		if actual != expected {
+			t.Errorf("Expected:\n%#v\n\nGot:\n%#v", expected, actual)
+		}
+
+		_, err = FromTemplate(col, &Options{Template: tmpl}, map[string]string{"value": "forty-two"})
+		if err == nil {
+			t.Errorf("Expected an error filling in a %s column with text", datatype)
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	col := fakeColumn("text", "text")
+	cases := []string{
+		"{{firstname",           // unterminated
+		"{{nope}}",              // unknown generator
+		"{{.missing}}",          // unknown column
+		"{{intrange 1 2 3}}",    // too many parameters
+		"{{sentence \"many\"}}", // bad parameter
+	}
+
+	for _, text := range cases {
+		_, err := ParseTemplate(col, text, []string{"test"})
+		if err == nil {
+			t.Errorf("Expected an error parsing the template %q", text)
+		}
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
 	"math/rand/v2"
 	"os"
+	"slices"
 
 	"github.com/brianvoe/gofakeit/v7"
+	_ "github.com/go-sql-driver/mysql"
 	"github.com/goccy/go-yaml"
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 
 	"dummy/commands"
+	"dummy/output"
 	"dummy/sqldatabase"
 	"dummy/sqldatabase/drivers"
 	"dummy/sqldatabase/table"
@@ -21,13 +26,33 @@ func main() {
 		path         string
 		seed         int
 		defaultCount int
+		apply        bool
+		dryRun       bool
+		format       string
+		outputDir    string
 	)
 
 	flag.StringVar(&path, "path", "dummy.yml", "Path to the configuration yaml file.")
 	flag.IntVar(&seed, "seed", rand.Int(), "Set the seeder used to generate the output.")
 	flag.IntVar(&defaultCount, "count", 10, "Change the default record generation count for each table.")
+	flag.BoolVar(&apply, "apply", false, "Insert the generated data into the database instead of printing it.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the generated data as SQL without touching the database (default).")
+	flag.StringVar(&format, "format", "insert", "Write the generated data as \"insert\" statements, \"copy\" blocks for psql or \"csv\", \"json\" or \"ndjson\" files.")
+	flag.StringVar(&outputDir, "output-dir", ".", "Directory to write a file for each table to when the format is csv, json or ndjson.")
 	flag.Parse()
 
+	if apply && dryRun {
+		panic("--apply and --dry-run cannot be used together")
+	}
+
+	if format != "insert" && format != "copy" && !output.IsFormat(format) {
+		panic("unsupported format: \"" + format + "\"")
+	}
+
+	if apply && format != "insert" {
+		panic("--format " + format + " can only be printed, not applied")
+	}
+
 	configFile, err := os.ReadFile(path)
 	if err != nil {
 		panic("could not find file specified")
@@ -42,72 +67,303 @@ func main() {
 	}
 	gofakeit.Seed(config.Options.Seed)
 
-	if !config.Options.HideInputComment {
-		fmt.Println("-- host:", config.Server.Host)
-		fmt.Println("-- name:", config.Server.Name)
-		fmt.Println("-- user:", config.Server.User)
-		fmt.Println("-- seed:", config.Options.Seed)
-		fmt.Println("")
+	if config.Options.NullRatio < 0 || config.Options.NullRatio > 1 {
+		panic("nullRatio must be between 0 and 1")
 	}
 
-	driver, err := drivers.NewPostgresqlDriver(config.Server.User, config.Server.Password, config.Server.Host, config.Server.Name)
-	if err != nil {
-		panic("(drivers.NewPostgresqlDriver): " + err.Error())
+	if !table.IsDefaultPolicy(config.Options.Defaults) {
+		panic("defaults must be one of generate, default or omit")
+	}
+
+	if config.Options.BatchSize < 0 {
+		panic("batchSize must not be negative")
+	}
+
+	if !table.IsIdentityPolicy(config.Options.Identities) {
+		panic("identities must be one of default or explicit")
+	}
+
+	// Rows are written as they're generated, which would otherwise mean a
+	// write to stdout for every statement
+	stdout := bufio.NewWriter(os.Stdout)
+	defer stdout.Flush()
+
+	if !config.Options.HideInputComment && !apply && !output.IsFormat(format) {
+		fmt.Fprintln(stdout, "-- host:", config.Server.Host)
+		fmt.Fprintln(stdout, "-- name:", config.Server.Name)
+		fmt.Fprintln(stdout, "-- user:", config.Server.User)
+		fmt.Fprintln(stdout, "-- seed:", config.Options.Seed)
+		fmt.Fprintln(stdout, "")
+	}
+
+	// Tables without a schema in their name are looked for in the default
+	// schema, which depends on the database we're connected to
+	defaultSchema := config.Server.Schema
+
+	var driver drivers.SqlDatabaseDriver
+	switch config.Server.Driver {
+	case "", "postgres", "postgresql":
+		if defaultSchema == "" {
+			defaultSchema = "public"
+		}
+
+		driver, err = drivers.NewPostgresqlDriver(config.Server.User, config.Server.Password, config.Server.Host, config.Server.Name)
+		if err != nil {
+			panic("(drivers.NewPostgresqlDriver): " + err.Error())
+		}
+	case "mysql", "mariadb":
+		if defaultSchema == "" {
+			defaultSchema = config.Server.Name
+		}
+
+		driver, err = drivers.NewMySQLDriver(config.Server.User, config.Server.Password, config.Server.Host, config.Server.Name, tableSchemas(config.Tables, defaultSchema))
+		if err != nil {
+			panic("(drivers.NewMySQLDriver): " + err.Error())
+		}
+	case "sqlite", "sqlite3":
+		if defaultSchema == "" {
+			defaultSchema = "main"
+		}
+
+		driver, err = drivers.NewSqliteDriver(config.Server.Path)
+		if err != nil {
+			panic("(drivers.NewSqliteDriver): " + err.Error())
+		}
+	case "ddl":
+		if defaultSchema == "" {
+			defaultSchema = "public"
+		}
+
+		driver, err = drivers.NewDdlDriver(config.Server.Path)
+		if err != nil {
+			panic("(drivers.NewDdlDriver): " + err.Error())
+		}
+	default:
+		panic("unsupported driver: \"" + config.Server.Driver + "\"")
+	}
+	if db := driver.Database(); db != nil {
+		defer db.Close()
+	}
+
+	copyDriver, canCopy := driver.(drivers.CopyDriver)
+	if format == "copy" && !canCopy {
+		panic("--format copy is not supported by the \"" + config.Server.Driver + "\" driver")
 	}
-	defer driver.Database().Close()
 
 	sqlDb, err := sqldatabase.New(driver)
 	if err != nil {
 		panic("(sqldatabase.New): " + err.Error())
 	}
 
-	for i, tbl := range config.Tables {
-		if i > 0 {
-			fmt.Print("\n\n")
+	tables := make([]*table.Table, 0, len(config.Tables))
+	counts := make(map[string]int)
+	for _, tbl := range config.Tables {
+		t := table.NewTable(tbl.Name)
+		if t.Schema == "" {
+			t.Schema = defaultSchema
 		}
 
-		t := table.NewTable(tbl.Name)
-		columns, err := sqlDb.Driver.TableColumns(t.Name)
+		columns, err := sqlDb.Driver.TableColumns(t.Schema, t.Name)
 		if err != nil {
 			panic("(sqlDb.Driver.TableColumns): " + err.Error())
 		}
 		t.Columns = columns
+		t.Metadata.NullRatio = config.Options.NullRatio
+		t.Metadata.DefaultPolicy = config.Options.Defaults
+		t.Metadata.IdentityPolicy = config.Options.Identities
+		t.Metadata.BatchSize = config.Options.BatchSize
+
+		uniques, err := sqlDb.Driver.UniqueConstraints(t.Schema, t.Name)
+		if err != nil {
+			panic("(sqlDb.Driver.UniqueConstraints): " + err.Error())
+		}
+		t.Metadata.UniqueConstraints = uniques
 
-		err = t.Validate(tbl, sqlDb.ForeignKeys[tbl.Name])
+		err = t.Validate(tbl, sqlDb.ForeignKeys[t.QualifiedName()])
 		if err != nil {
 			panic(err)
 		}
 
+		ignored, err := sqlDb.ResolveCheckConstraints(t)
+		if err != nil {
+			panic("(sqlDb.ResolveCheckConstraints): " + err.Error())
+		}
+
+		for _, err := range ignored {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		}
+
 		t.GuessCustomTextFieldGenerators()
 
-		{
-			var count int
-			if tbl.Count != 0 {
-				count = tbl.Count
-			} else {
-				count = defaultCount
+		if tbl.Count != 0 {
+			counts[t.QualifiedName()] = tbl.Count
+		} else {
+			counts[t.QualifiedName()] = defaultCount
+		}
+
+		tables = append(tables, t)
+	}
+
+	// Referenced tables need to be generated (and inserted) first
+	tables, err = sqlDb.SortTables(tables)
+	if err != nil {
+		panic("(sqlDb.SortTables): " + err.Error())
+	}
+
+	err = sqlDb.ResolveIdentities(tables)
+	if err != nil {
+		panic("(sqlDb.ResolveIdentities): " + err.Error())
+	}
+
+	// Rows are dropped once they're written so the values other tables
+	// reference have to be kept aside as they're generated
+	sqlDb.ResolveReferences(tables)
+
+	var inserter *sqldatabase.Inserter
+	if apply {
+		inserter, err = sqlDb.NewInserter()
+		if err != nil {
+			panic("(sqlDb.NewInserter): " + err.Error())
+		}
+	}
+
+	// Wrap the printed script so it's loaded in full or not at all
+	transaction := config.Options.Transaction && !apply && !output.IsFormat(format)
+	if transaction {
+		stdout.WriteString("BEGIN;\n\n")
+	}
+
+	inserted := make([]int64, len(tables))
+	for i, t := range tables {
+		err = sqlDb.ResolveForeignKeys(t, tables)
+		if err != nil {
+			panic("(sqlDb.ResolveForeignKeys): " + err.Error())
+		}
+
+		count := counts[t.QualifiedName()]
+
+		if apply {
+			err = t.StreamData(count, func() error {
+				rows, err := inserter.Insert(t)
+				inserted[i] += rows
+				return err
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			err = inserter.UpdateSequences(t)
+			if err != nil {
+				panic("(inserter.UpdateSequences): " + err.Error())
+			}
+
+			continue
+		}
+
+		if output.IsFormat(format) {
+			file, err := output.Create(outputDir, format, t)
+			if err != nil {
+				panic("(output.Create): " + err.Error())
 			}
 
-			err = t.CreateData(count)
+			writer, err := output.NewWriter(file, format, t)
+			if err != nil {
+				panic("(output.NewWriter): " + err.Error())
+			}
+
+			err = t.StreamData(count, writer.Write)
+			if err == nil {
+				err = writer.Close()
+			}
+			if err == nil {
+				err = file.Close()
+			}
 			if err != nil {
 				panic(err)
 			}
+
+			fmt.Fprintf(stdout, "%s: %d rows written to %s\n", t.QualifiedName(), t.Metadata.CreatedRows, file.Name())
+			continue
+		}
+
+		if i > 0 {
+			stdout.WriteString("\n\n")
+		}
+
+		err = t.StreamData(count, func() error {
+			if format == "copy" {
+				statement, err := copyDriver.CopyStatement(t)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintln(stdout, statement)
+				return nil
+			}
+
+			for _, statement := range sqlDb.InsertStatements(t) {
+				fmt.Fprintln(stdout, statement)
+			}
+
+			return nil
+		})
+		if err != nil {
+			panic(err)
 		}
 
-		fmt.Println(sqlDb.Driver.InsertStatement(t))
+		for _, statement := range sqlDb.Driver.SequenceStatements(t) {
+			fmt.Fprintln(stdout, statement)
+		}
 	}
+
+	if transaction {
+		stdout.WriteString("\nCOMMIT;\n")
+	}
+
+	if apply {
+		err = inserter.Commit()
+		if err != nil {
+			panic("(inserter.Commit): " + err.Error())
+		}
+
+		for i, t := range tables {
+			fmt.Fprintf(stdout, "%s: %d rows inserted\n", t.QualifiedName(), inserted[i])
+		}
+	}
+}
+
+// tableSchemas lists the schemas of the configured tables, starting with the
+// default schema of those without one in their name
+func tableSchemas(tables []commands.TableCommands, defaultSchema string) []string {
+	schemas := []string{defaultSchema}
+	for _, tbl := range tables {
+		schema := table.NewTable(tbl.Name).Schema
+		if schema != "" && !slices.Contains(schemas, schema) {
+			schemas = append(schemas, schema)
+		}
+	}
+
+	return schemas
 }
 
 type Config struct {
 	Server struct {
+		Driver   string `yaml:"driver"`
 		Host     string `yaml:"host"`
 		Name     string `yaml:"name"`
 		User     string `yaml:"user"`
 		Password string `yaml:"password"`
+		Path     string `yaml:"path"`
+		Schema   string `yaml:"schema"`
 	}
 	Options struct {
-		Seed             int  `yaml:"seed"`
-		HideInputComment bool `yaml:"hideInputComments"`
+		Seed             int     `yaml:"seed"`
+		HideInputComment bool    `yaml:"hideInputComments"`
+		NullRatio        float64 `yaml:"nullRatio"`
+		Defaults         string  `yaml:"defaults"`
+		Identities       string  `yaml:"identities"`
+		BatchSize        int     `yaml:"batchSize"`
+		Transaction      bool    `yaml:"transaction"`
 	}
 	Tables []commands.TableCommands `yaml:"tables"`
 }
@@ -0,0 +1,280 @@
+// Package output writes the rows generated for a table in formats meant for
+// use outside of SQL.
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/value"
+)
+
+// The formats rows can be written in
+const (
+	FormatCSV    = "csv"    // comma separated values with a header, NULL as \N
+	FormatJSON   = "json"   // a JSON array of objects
+	FormatNDJSON = "ndjson" // a JSON object on each line
+)
+
+// IsFormat checks if the format is one the rows can be written in.
+func IsFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSON || format == FormatNDJSON
+}
+
+// Create creates the file named after the table in dir that its rows are
+// written to.
+func Create(dir, format string, t *Table) (*os.File, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Create(filepath.Join(dir, t.QualifiedName()+"."+format))
+}
+
+// Writer writes the rows of a table as they're generated, a batch at a time,
+// so the table never has to be held in memory in full.
+type Writer struct {
+	w       *bufio.Writer
+	csv     *csv.Writer
+	format  string
+	table   *Table
+	columns []int
+	started bool
+	rows    int
+}
+
+// NewWriter creates a Writer for the rows of a table in the given format.
+func NewWriter(w io.Writer, format string, t *Table) (*Writer, error) {
+	if !IsFormat(format) {
+		return nil, errors.New("Unsupported output format \"" + format + "\"")
+	}
+
+	buffered := bufio.NewWriter(w)
+	return &Writer{w: buffered, csv: csv.NewWriter(buffered), format: format, table: t}, nil
+}
+
+// Write writes the rows currently held in the table's InsertRows.
+func (w *Writer) Write() error {
+	columns, err := Columns(w.table)
+	if err != nil {
+		return err
+	}
+
+	if !w.started {
+		err = w.start(columns)
+		if err != nil {
+			return err
+		}
+	} else if !slices.Equal(columns, w.columns) {
+		return errors.New("Table " + w.table.QualifiedName() + " mixes DEFAULT with other values across its rows")
+	}
+
+	if w.format == FormatCSV {
+		for _, row := range w.table.InsertRows {
+			var record []string
+			for _, i := range columns {
+				record = append(record, csvField(row[i]))
+			}
+
+			err = w.csv.Write(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	objects, err := jsonObjects(w.table, columns)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if w.format == FormatNDJSON {
+			_, err = w.w.WriteString(object + "\n")
+		} else if w.rows > 0 {
+			_, err = w.w.WriteString(",\n  " + object)
+		} else {
+			_, err = w.w.WriteString("\n  " + object)
+		}
+		if err != nil {
+			return err
+		}
+
+		w.rows += 1
+	}
+
+	return nil
+}
+
+// Close finishes off the output once every row has been written. It doesn't
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if !w.started {
+		columns, err := Columns(w.table)
+		if err != nil {
+			return err
+		}
+
+		err = w.start(columns)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.format == FormatJSON {
+		_, err := w.w.WriteString("\n]\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+
+	return w.w.Flush()
+}
+
+// start writes what comes before the first row, which is the header for CSV
+// and the opening of the array for JSON
+func (w *Writer) start(columns []int) error {
+	w.columns = columns
+	w.started = true
+
+	switch w.format {
+	case FormatCSV:
+		var header []string
+		for _, i := range columns {
+			header = append(header, w.table.Columns[i].Name)
+		}
+
+		return w.csv.Write(header)
+	case FormatJSON:
+		_, err := w.w.WriteString("[")
+		return err
+	}
+
+	return nil
+}
+
+// Columns returns the positions of the columns that have values of their
+// own. Columns left for the database to fill in are DEFAULT in every row and
+// have nothing to write.
+func Columns(t *Table) ([]int, error) {
+	var columns []int
+	for i, col := range t.Columns {
+		defaults := 0
+		for _, row := range t.InsertRows {
+			if row[i] == Default {
+				defaults += 1
+			}
+		}
+
+		if defaults > 0 && defaults < len(t.InsertRows) {
+			return nil, errors.New("Column '" + col.Name + "' of table " + t.QualifiedName() + " mixes DEFAULT with other values")
+		}
+
+		if defaults == 0 && !slices.Contains(t.Metadata.OmittedColumns, i) {
+			columns = append(columns, i)
+		}
+	}
+
+	return columns, nil
+}
+
+// NullCSV is written in place of NULL in CSV so that it's told apart from an
+// empty string. MySQL's LOAD DATA reads it as NULL as it is and PostgreSQL's
+// COPY does with the NULL '\N' option.
+const NullCSV = `\N`
+
+// csvField converts a value into a CSV field
+func csvField(value Value) string {
+	if value == Null {
+		return NullCSV
+	}
+
+	return Text(value)
+}
+
+// jsonObjects encodes each row as a JSON object with its keys in the same
+// order as the table's columns
+func jsonObjects(t *Table, columns []int) ([]string, error) {
+	objects := make([]string, 0, len(t.InsertRows))
+	for _, row := range t.InsertRows {
+		var object strings.Builder
+		object.WriteRune('{')
+		for j, i := range columns {
+			col := t.Columns[i]
+			value := jsonValue(row[i])
+
+			// JSON columns hold JSON, which is better written out as it is
+			if text, ok := row[i].(string); ok && (col.DataType == "json" || col.DataType == "jsonb") && json.Valid([]byte(text)) {
+				value = json.RawMessage(text)
+			}
+
+			key, _ := marshal(col.Name)
+			encoded, err := marshal(value)
+			if err != nil {
+				return nil, errors.New("Column '" + col.Name + "' of table " + t.QualifiedName() + ": " + err.Error())
+			}
+
+			if j > 0 {
+				object.WriteRune(',')
+			}
+
+			object.Write(key)
+			object.WriteRune(':')
+			object.Write(encoded)
+		}
+
+		object.WriteRune('}')
+		objects = append(objects, object.String())
+	}
+
+	return objects, nil
+}
+
+// marshal encodes a value as JSON, leaving characters like < and > as they
+// are rather than escaping them for HTML
+func marshal(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(value)
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), err
+}
+
+// jsonValue converts a value into one that encodes as the matching JSON type
+func jsonValue(value Value) any {
+	switch value := value.(type) {
+	case Marker:
+		return nil
+	case bool, int64, string:
+		return value
+	case Number:
+		return json.Number(value)
+	case Array:
+		elements := make([]any, 0, len(value.Elements))
+		for _, element := range value.Elements {
+			elements = append(elements, jsonValue(element))
+		}
+
+		return elements
+	default:
+		return Text(value)
+	}
+}
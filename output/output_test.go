@@ -0,0 +1,134 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/value"
+)
+
+func createFakeTable() *Table {
+	table := NewTable("testing.todos")
+	table.Columns = []Column{
+		{Name: "id", DataType: "integer", IsNullable: "NO", IsIdentity: "YES"},
+		{Name: "task", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "price", DataType: "numeric", IsNullable: "YES", IsIdentity: "NO"},
+		{Name: "tags", DataType: "ARRAY", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "extra", DataType: "jsonb", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	table.InsertRows = append(table.InsertRows, []Value{Default, `Say "hi", it's me`, Number("12.50"), Array{ElementType: "text", Dimensions: 1, Elements: []Value{"a", "b,c"}}, `{"done": true}`})
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Walk", Null, Array{ElementType: "text", Dimensions: 1, Elements: []Value{}}, "[1, 2]"})
+
+	return table
+}
+
+// write writes every row of the table in the given format in one go
+func write(t *testing.T, format string, table *Table) string {
+	var actual strings.Builder
+	writer, err := NewWriter(&actual, format, table)
+	if err != nil {
+		t.Fatalf("Error calling NewWriter: %s", err)
+	}
+
+	err = writer.Write()
+	if err != nil {
+		t.Fatalf("Error calling Write: %s", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("Error calling Close: %s", err)
+	}
+
+	return actual.String()
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := createFakeTable()
+	table.InsertRows[1][1] = ""
+	actual := write(t, FormatCSV, table)
+
+	// NULL is told apart from an empty string
+	expected := `task,price,tags,extra
+"Say ""hi"", it's me",12.50,"{""a"",""b,c""}","{""done"": true}"
+,\N,{},"[1, 2]"
+`
+
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	actual := write(t, FormatJSON, createFakeTable())
+
+	expected := `[
+  {"task":"Say \"hi\", it's me","price":12.50,"tags":["a","b,c"],"extra":{"done":true}},
+  {"task":"Walk","price":null,"tags":[],"extra":[1,2]}
+]
+`
+
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	actual := write(t, FormatNDJSON, createFakeTable())
+
+	expected := `{"task":"Say \"hi\", it's me","price":12.50,"tags":["a","b,c"],"extra":{"done":true}}
+{"task":"Walk","price":null,"tags":[],"extra":[1,2]}
+`
+
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestColumnsMixedDefault(t *testing.T) {
+	table := createFakeTable()
+	table.InsertRows[1][0] = int64(2)
+
+	_, err := Columns(table)
+	if err == nil {
+		t.Errorf("Expected an error mixing DEFAULT with other values")
+	}
+}
+
+func TestWriterBatches(t *testing.T) {
+	table := createFakeTable()
+	rows := table.InsertRows
+
+	var actual strings.Builder
+	writer, err := NewWriter(&actual, FormatJSON, table)
+	if err != nil {
+		t.Fatalf("Error calling NewWriter: %s", err)
+	}
+
+	for _, row := range rows {
+		table.InsertRows = [][]Value{row}
+		err = writer.Write()
+		if err != nil {
+			t.Fatalf("Error calling Write: %s", err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("Error calling Close: %s", err)
+	}
+
+	// The same as writing both rows at once
+	expected := `[
+  {"task":"Say \"hi\", it's me","price":12.50,"tags":["a","b,c"],"extra":{"done":true}},
+  {"task":"Walk","price":null,"tags":[],"extra":[1,2]}
+]
+`
+
+	if actual.String() != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual.String())
+	}
+}
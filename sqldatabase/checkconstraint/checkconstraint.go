@@ -0,0 +1,30 @@
+package checkconstraint
+
+// CheckConstraint is a CHECK constraint on a table. The definition is the
+// constraint's expression as the database or schema file describes it.
+type CheckConstraint struct {
+	TableSchema    string
+	TableName      string
+	ConstraintName string
+	Definition     string
+}
+
+// Condition is one of the conditions joined together by AND that make up
+// a CHECK constraint.
+type Condition struct {
+	ColumnName string
+
+	// Operator is one of <, <=, >, >=, =, <>, IN, IS NOT NULL or ~ and ~*
+	// for matching a pattern, case sensitively or not
+	Operator string
+
+	// Length compares the number of characters in the column rather than
+	// the column's value
+	Length bool
+
+	// The column is compared to a literal value, a list of literal values
+	// for IN or to another column
+	Value           string
+	Values          []string
+	OtherColumnName string
+}
@@ -1,6 +1,9 @@
 package column
 
-import "database/sql"
+import (
+	"database/sql"
+	"strings"
+)
 
 type Column struct {
 	Name                   string
@@ -14,6 +17,7 @@ type Column struct {
 	NumericPrecisionRadix  sql.NullInt32
 	NumericScale           sql.NullInt32
 	DatetimePrecision      sql.NullInt16
+	UdtSchema              string
 	UdtName                string
 	IsSelfReferencing      string
 	IsIdentity             string
@@ -23,4 +27,18 @@ type Column struct {
 	IdentityMaximum        sql.NullInt32
 	IdentityMinimum        sql.NullInt32
 	IsUpdateable           string
+
+	// EnumValues lists the values allowed in enum and set columns
+	EnumValues []string
+
+	// ArrayDimensions is the number of dimensions declared for an array
+	// column, which may be 0 when the database doesn't know
+	ArrayDimensions int
+}
+
+// IsAutoIncrement checks if the database fills in the column's value by
+// itself, either as an identity column or from a sequence the way serial
+// columns do
+func (c Column) IsAutoIncrement() bool {
+	return c.IsIdentity == "YES" || (c.ColumnDefault.Valid && strings.HasPrefix(c.ColumnDefault.String, "nextval("))
 }
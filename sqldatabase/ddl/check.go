@@ -0,0 +1,422 @@
+package ddl
+
+import (
+	"errors"
+	"strings"
+
+	. "dummy/sqldatabase/checkconstraint"
+)
+
+// ParseCheck breaks the expression of a CHECK constraint into the conditions
+// it's made up of. The expression may be given the way it's written in a
+// schema or the way pg_get_constraintdef describes it. Only comparisons of a
+// column, or a column's length, to a literal or another column are understood
+// along with BETWEEN, IN, = ANY (ARRAY[...]), IS NOT NULL and matching a
+// column to a pattern with ~, ~*, REGEXP or RLIKE.
+func ParseCheck(definition string) ([]Condition, error) {
+	tokens, err := tokenize(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = withoutCasts(tokens)
+	if len(tokens) > 0 && tokens[0].is("check") {
+		tokens = tokens[1:]
+	}
+
+	// Drop the options pg_get_constraintdef adds after the expression
+	for len(tokens) > 1 {
+		last := tokens[len(tokens)-2:]
+		if !(last[0].is("not") && last[1].is("valid")) && !(last[0].is("no") && last[1].is("inherit")) {
+			break
+		}
+		tokens = tokens[:len(tokens)-2]
+	}
+
+	c := &checkParser{src: definition}
+	return c.conditions(tokens)
+}
+
+type checkParser struct {
+	src string
+}
+
+func (c *checkParser) conditions(tokens []token) ([]Condition, error) {
+	tokens = unwrap(tokens)
+
+	parts := splitAnd(tokens)
+	if len(parts) == 1 {
+		return c.condition(tokens)
+	}
+
+	var conditions []Condition
+	for _, part := range parts {
+		partConditions, err := c.conditions(part)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, partConditions...)
+	}
+
+	return conditions, nil
+}
+
+func (c *checkParser) condition(tokens []token) ([]Condition, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("CHECK constraint is missing an expression")
+	}
+
+	// Pattern matches only ever compare a column to a pattern
+	for i, t := range tokens {
+		if t.kind != tokenSymbol || (t.text != "~" && t.text != "~*") || depth(tokens[:i]) != 0 {
+			continue
+		}
+
+		return c.match(tokens, tokens[:i], t.text, tokens[i+1:])
+	}
+
+	// Comparisons are the most common so look for them first
+	for i, t := range tokens {
+		if t.kind != tokenSymbol || !isComparison(t.text) || depth(tokens[:i]) != 0 {
+			continue
+		}
+
+		operator := t.text
+		if operator == "!=" {
+			operator = "<>"
+		}
+
+		left, right := tokens[:i], tokens[i+1:]
+		if operator == "=" && len(right) > 0 && (right[0].is("any") || right[0].is("some")) {
+			column, ok := c.column(left)
+			values, valuesOk := c.arrayValues(right[1:])
+			if !ok || !valuesOk {
+				return nil, c.unknown(tokens)
+			}
+
+			return []Condition{{ColumnName: column, Operator: "IN", Values: values}}, nil
+		}
+
+		return c.comparison(tokens, left, operator, right)
+	}
+
+	// Look for the keyword forms after the column
+	for i, t := range tokens {
+		if i == 0 || t.kind != tokenIdentifier || depth(tokens[:i]) != 0 {
+			continue
+		}
+
+		column, ok := c.column(tokens[:i])
+		rest := tokens[i:]
+		switch {
+		case !ok:
+			return nil, c.unknown(tokens)
+		case len(rest) == 3 && rest[0].is("is") && rest[1].is("not") && rest[2].is("null"):
+			return []Condition{{ColumnName: column, Operator: "IS NOT NULL"}}, nil
+		case rest[0].is("between"):
+			for j := 1; j < len(rest); j++ {
+				if !rest[j].is("and") || depth(rest[1:j]) != 0 {
+					continue
+				}
+
+				low, lowOk := c.literal(rest[1:j])
+				high, highOk := c.literal(rest[j+1:])
+				if !lowOk || !highOk {
+					return nil, c.unknown(tokens)
+				}
+
+				return []Condition{
+					{ColumnName: column, Operator: ">=", Value: low},
+					{ColumnName: column, Operator: "<=", Value: high},
+				}, nil
+			}
+		case rest[0].is("regexp") || rest[0].is("rlike"):
+			return c.match(tokens, tokens[:i], "~", rest[1:])
+		case rest[0].is("in"):
+			values, ok := c.list(rest[1:])
+			if !ok {
+				return nil, c.unknown(tokens)
+			}
+
+			return []Condition{{ColumnName: column, Operator: "IN", Values: values}}, nil
+		}
+
+		break
+	}
+
+	return nil, c.unknown(tokens)
+}
+
+// comparison reads a comparison between a column, or a column's length, and
+// a literal or another column. The column is moved to the left hand side.
+func (c *checkParser) comparison(tokens, left []token, operator string, right []token) ([]Condition, error) {
+	if _, ok := c.column(left); !ok {
+		if _, ok := c.length(left); !ok {
+			left, right = right, left
+			operator = flipped[operator]
+		}
+	}
+
+	condition := Condition{Operator: operator}
+	if column, ok := c.column(left); ok {
+		condition.ColumnName = column
+	} else if column, ok := c.length(left); ok {
+		condition.ColumnName = column
+		condition.Length = true
+	} else {
+		return nil, c.unknown(tokens)
+	}
+
+	if value, ok := c.literal(right); ok {
+		condition.Value = value
+	} else if other, ok := c.column(right); ok && !condition.Length {
+		condition.OtherColumnName = other
+	} else {
+		return nil, c.unknown(tokens)
+	}
+
+	return []Condition{condition}, nil
+}
+
+// match reads a column being matched to a pattern given as a string
+func (c *checkParser) match(tokens, left []token, operator string, right []token) ([]Condition, error) {
+	column, ok := c.column(left)
+	pattern, patternOk := c.literal(right)
+	if !ok || !patternOk || !strings.HasPrefix(pattern, "'") {
+		return nil, c.unknown(tokens)
+	}
+
+	return []Condition{{ColumnName: column, Operator: operator, Value: pattern}}, nil
+}
+
+// column reads a lone column name
+func (c *checkParser) column(tokens []token) (string, bool) {
+	tokens = unwrap(tokens)
+	if len(tokens) != 1 {
+		return "", false
+	}
+
+	t := tokens[0]
+	switch {
+	case t.kind == tokenQuotedIdentifier:
+		return t.text, true
+	case t.kind == tokenIdentifier && !t.is("true") && !t.is("false") && !t.is("null"):
+		return strings.ToLower(t.text), true
+	default:
+		return "", false
+	}
+}
+
+// length reads the column out of a call to length(column)
+func (c *checkParser) length(tokens []token) (string, bool) {
+	tokens = unwrap(tokens)
+	if len(tokens) < 3 || !(tokens[0].is("length") || tokens[0].is("char_length") || tokens[0].is("character_length")) {
+		return "", false
+	}
+
+	if !tokens[1].is("(") || !tokens[len(tokens)-1].is(")") {
+		return "", false
+	}
+
+	return c.column(tokens[2 : len(tokens)-1])
+}
+
+// literal reads a number, string or boolean as it would be written in SQL
+func (c *checkParser) literal(tokens []token) (string, bool) {
+	tokens = unwrap(tokens)
+
+	// MySQL puts the character set in front of strings
+	if len(tokens) == 2 && tokens[0].kind == tokenIdentifier && strings.HasPrefix(tokens[0].text, "_") && tokens[1].kind == tokenString {
+		tokens = tokens[1:]
+	}
+
+	sign := ""
+	if len(tokens) == 2 && (tokens[0].is("-") || tokens[0].is("+")) && tokens[1].kind == tokenNumber {
+		sign = strings.TrimPrefix(tokens[0].text, "+")
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) != 1 {
+		return "", false
+	}
+
+	t := tokens[0]
+	switch {
+	case t.kind == tokenNumber:
+		return sign + t.text, true
+	case t.kind == tokenString && sign == "":
+		return "'" + strings.ReplaceAll(t.text, "'", "''") + "'", true
+	case t.is("true") || t.is("false"):
+		return strings.ToLower(t.text), true
+	default:
+		return "", false
+	}
+}
+
+// list reads a parenthesised list of literals
+func (c *checkParser) list(tokens []token) ([]string, bool) {
+	if len(tokens) < 2 || !tokens[0].is("(") || !tokens[len(tokens)-1].is(")") {
+		return nil, false
+	}
+
+	var values []string
+	for _, element := range split(tokens[1:len(tokens)-1], ",") {
+		value, ok := c.literal(element)
+		if !ok {
+			return nil, false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, len(values) > 0
+}
+
+// arrayValues reads the literals out of (ARRAY[...])
+func (c *checkParser) arrayValues(tokens []token) ([]string, bool) {
+	tokens = unwrap(tokens)
+	if len(tokens) < 3 || !tokens[0].is("array") || !tokens[1].is("[") || !tokens[len(tokens)-1].is("]") {
+		return nil, false
+	}
+
+	var values []string
+	for _, element := range split(tokens[2:len(tokens)-1], ",") {
+		value, ok := c.literal(element)
+		if !ok {
+			return nil, false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, len(values) > 0
+}
+
+func (c *checkParser) unknown(tokens []token) error {
+	text := ""
+	if len(tokens) > 0 {
+		text = c.src[tokens[0].start:tokens[len(tokens)-1].end]
+	}
+
+	return errors.New("Cannot understand CHECK expression: " + text)
+}
+
+var flipped = map[string]string{
+	"<":  ">",
+	"<=": ">=",
+	">":  "<",
+	">=": "<=",
+	"=":  "=",
+	"<>": "<>",
+	"!=": "<>",
+}
+
+func isComparison(symbol string) bool {
+	_, ok := flipped[symbol]
+	return ok
+}
+
+// depth returns how many parentheses or brackets are left open
+func depth(tokens []token) int {
+	depth := 0
+	for _, t := range tokens {
+		if t.is("(") || t.is("[") {
+			depth += 1
+		} else if t.is(")") || t.is("]") {
+			depth -= 1
+		}
+	}
+
+	return depth
+}
+
+// unwrap removes the parentheses around the whole of an expression
+func unwrap(tokens []token) []token {
+	for len(tokens) >= 2 && tokens[0].is("(") && tokens[len(tokens)-1].is(")") && closes(tokens) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	return tokens
+}
+
+// closes returns the position of the parenthesis closing the first token
+func closes(tokens []token) int {
+	depth := 0
+	for i, t := range tokens {
+		if t.is("(") {
+			depth += 1
+		} else if t.is(")") {
+			depth -= 1
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitAnd breaks up an expression at every top level AND, leaving alone the
+// AND that belongs to a BETWEEN
+func splitAnd(tokens []token) [][]token {
+	var parts [][]token
+
+	start := 0
+	between := false
+	for i, t := range tokens {
+		if depth(tokens[start:i]) != 0 {
+			continue
+		}
+
+		switch {
+		case t.is("between"):
+			between = true
+		case t.is("and") && between:
+			between = false
+		case t.is("and"):
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, tokens[start:])
+}
+
+// withoutCasts drops the casts Postgres adds to the literals in a CHECK
+// constraint, such as (0)::numeric or 'a'::character varying
+func withoutCasts(tokens []token) []token {
+	var kept []token
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].is("::") {
+			kept = append(kept, tokens[i])
+			continue
+		}
+
+		// Type names can be more than one word long
+		for i+1 < len(tokens) && (tokens[i+1].kind == tokenIdentifier || tokens[i+1].kind == tokenQuotedIdentifier || tokens[i+1].is(".")) && !isCastEnd(tokens[i+1]) {
+			i += 1
+		}
+
+		if i+1 < len(tokens) && tokens[i+1].is("(") {
+			i += closes(tokens[i+1:]) + 1
+		}
+
+		for i+2 < len(tokens) && tokens[i+1].is("[") && tokens[i+2].is("]") {
+			i += 2
+		}
+	}
+
+	return kept
+}
+
+func isCastEnd(t token) bool {
+	for _, keyword := range []string{"and", "or", "between", "in", "is", "not", "like", "any", "some", "all"} {
+		if t.is(keyword) {
+			return true
+		}
+	}
+
+	return false
+}
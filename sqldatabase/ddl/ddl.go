@@ -0,0 +1,1042 @@
+package ddl
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/uniqueconstraint"
+)
+
+// Schema holds the tables described by a file of DDL statements.
+type Schema struct {
+	Tables      []*Table
+	ForeignKeys []ForeignKeyRelation
+
+	// Enums holds the labels of each enum type, keyed by the type's name
+	Enums map[string][]string
+}
+
+type Table struct {
+	Schema     string
+	Name       string
+	Columns    []Column
+	PrimaryKey []string
+
+	// UniqueConstraints holds the primary key along with the unique
+	// constraints and unique indexes of the table
+	UniqueConstraints []UniqueConstraint
+	CheckConstraints  []CheckConstraint
+}
+
+// Table looks up a table by its name, which may be qualified by a schema.
+func (s *Schema) Table(name string) *Table {
+	schema, name := splitName(name)
+	for _, t := range s.Tables {
+		if t.Name == name && (schema == "" || t.Schema == schema) {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Parse reads the tables, columns and constraints out of the CREATE TABLE
+// and ALTER TABLE statements in a schema file written for Postgres, along with
+// the enum types from CREATE TYPE and unique indexes from CREATE UNIQUE INDEX.
+// Every other kind of statement is skipped.
+func Parse(src string) (*Schema, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{Enums: make(map[string][]string)}
+	for _, statement := range split(tokens, ";") {
+		p := &parser{src: src, tokens: statement}
+
+		switch {
+		case p.accept("create"):
+			p.accept("or", "replace")
+			p.accept("global")
+			p.accept("local")
+			p.accept("temp")
+			p.accept("temporary")
+			p.accept("unlogged")
+
+			if p.accept("type") {
+				err = p.createType(schema)
+				break
+			}
+
+			if p.accept("unique", "index") {
+				err = p.createUniqueIndex(schema)
+				break
+			}
+
+			if !p.accept("table") {
+				continue
+			}
+
+			err = p.createTable(schema)
+		case p.accept("alter", "table"):
+			err = p.alterTable(schema)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Types are only known by name so enums can be declared after the
+	// tables that use them
+	for _, table := range schema.Tables {
+		for i := range table.Columns {
+			col := &table.Columns[i]
+			switch {
+			case col.DataType == "USER-DEFINED":
+				col.EnumValues = schema.Enums[col.UdtName]
+			case col.DataType == "ARRAY":
+				col.EnumValues = schema.Enums[strings.TrimPrefix(col.UdtName, "_")]
+			}
+		}
+	}
+
+	// FK constraints without a column list reference the primary key
+	for i := range schema.ForeignKeys {
+		fk := &schema.ForeignKeys[i]
+		if fk.ForeignColumnName != "" {
+			continue
+		}
+
+		parent := schema.Table(fk.ForeignTableSchema + "." + fk.ForeignTableName)
+		if parent == nil {
+			return nil, errors.New("FK constraint '" + fk.ConstraintName + "' references unknown table " + fk.ForeignTableName)
+		}
+
+		position := 0
+		for _, other := range schema.ForeignKeys[:i] {
+			if other.ConstraintName == fk.ConstraintName && other.TableName == fk.TableName {
+				position += 1
+			}
+		}
+
+		if position >= len(parent.PrimaryKey) {
+			return nil, errors.New("FK constraint '" + fk.ConstraintName + "' references table " + fk.ForeignTableName + " which has no matching primary key")
+		}
+
+		fk.ForeignColumnName = parent.PrimaryKey[position]
+	}
+
+	return schema, nil
+}
+
+// split breaks up tokens at every top level occurrence of the separator
+func split(tokens []token, separator string) [][]token {
+	var parts [][]token
+
+	depth := 0
+	start := 0
+	for i, t := range tokens {
+		switch {
+		case t.is("(") || t.is("["):
+			depth += 1
+		case t.is(")") || t.is("]"):
+			depth -= 1
+		case depth == 0 && t.is(separator):
+			if i > start {
+				parts = append(parts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+
+	return parts
+}
+
+func splitName(name string) (string, string) {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return "", name
+}
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: tokenSymbol}
+	}
+
+	return p.tokens[p.pos]
+}
+
+// accept consumes the given sequence of keywords or symbols if the
+// upcoming tokens match it
+func (p *parser) accept(words ...string) bool {
+	if p.pos+len(words) > len(p.tokens) {
+		return false
+	}
+
+	for i, word := range words {
+		if !p.tokens[p.pos+i].is(word) {
+			return false
+		}
+	}
+
+	p.pos += len(words)
+	return true
+}
+
+func (p *parser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return errors.New("Expected \"" + strings.Join(words, " ") + "\" but found \"" + p.peek().text + "\" in schema")
+	}
+
+	return nil
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenIdentifier:
+		p.pos += 1
+		return strings.ToLower(t.text), nil
+	case tokenQuotedIdentifier:
+		p.pos += 1
+		return t.text, nil
+	default:
+		return "", errors.New("Expected an identifier but found \"" + t.text + "\" in schema")
+	}
+}
+
+// qualifiedName reads a name that may be qualified by a schema, defaulting
+// to the public schema
+func (p *parser) qualifiedName() (string, string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	schema := "public"
+	for p.accept(".") {
+		schema = name
+		name, err = p.identifier()
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return schema, name, nil
+}
+
+func (p *parser) identifierList() ([]string, error) {
+	var names []string
+
+	err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		if p.accept(")") {
+			return names, nil
+		}
+
+		err = p.expect(",")
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// group consumes a parenthesised group and returns the source text inside it
+func (p *parser) group() (string, error) {
+	if !p.peek().is("(") {
+		return "", errors.New("Expected \"(\" but found \"" + p.peek().text + "\" in schema")
+	}
+
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		t := p.tokens[p.pos]
+		p.pos += 1
+
+		if t.is("(") {
+			depth += 1
+		} else if t.is(")") {
+			depth -= 1
+			if depth == 0 {
+				return p.src[p.tokens[start].end:t.start], nil
+			}
+		}
+	}
+
+	return "", errors.New("Unbalanced parentheses in schema")
+}
+
+func (p *parser) createTable(schema *Schema) error {
+	p.accept("if", "not", "exists")
+
+	schemaName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	table := &Table{Schema: schemaName, Name: name}
+	schema.Tables = append(schema.Tables, table)
+
+	if !p.peek().is("(") {
+		return nil // CREATE TABLE ... AS or PARTITION OF
+	}
+
+	start := p.pos + 1
+	_, err = p.group()
+	if err != nil {
+		return err
+	}
+
+	for _, element := range split(p.tokens[start:p.pos-1], ",") {
+		ep := &parser{src: p.src, tokens: element}
+
+		if ep.accept("like") {
+			continue
+		}
+
+		if ep.isTableConstraint() {
+			err = ep.tableConstraint(schema, table)
+		} else {
+			err = ep.column(schema, table)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createType reads the labels of an enum type. Other kinds of types are
+// skipped.
+func (p *parser) createType(schema *Schema) error {
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	if !p.accept("as", "enum") {
+		return nil
+	}
+
+	err = p.expect("(")
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, 0)
+	for !p.accept(")") {
+		if p.accept(",") {
+			continue
+		}
+
+		t := p.peek()
+		if t.kind != tokenString {
+			return errors.New("Expected a label in enum type " + name)
+		}
+
+		p.pos += 1
+		labels = append(labels, t.text)
+	}
+
+	schema.Enums[name] = labels
+	return nil
+}
+
+// createUniqueIndex reads the columns of a unique index. Partial indexes and
+// indexes on expressions are skipped since they don't stop the same values
+// from being used in every row.
+func (p *parser) createUniqueIndex(schema *Schema) error {
+	p.accept("concurrently")
+	p.accept("if", "not", "exists")
+
+	var indexName string
+	if !p.peek().is("on") {
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		indexName = name
+	}
+
+	err := p.expect("on")
+	if err != nil {
+		return err
+	}
+	p.accept("only")
+
+	schemaName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	table := schema.Table(schemaName + "." + name)
+	if table == nil {
+		return errors.New("CREATE UNIQUE INDEX references unknown table " + name)
+	}
+
+	if p.accept("using") {
+		_, err = p.identifier()
+		if err != nil {
+			return err
+		}
+	}
+
+	start := p.pos + 1
+	_, err = p.group()
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	for _, element := range split(p.tokens[start:p.pos-1], ",") {
+		if len(element) > 1 && element[1].is("(") {
+			return nil // an expression such as lower(email)
+		}
+
+		ep := &parser{src: p.src, tokens: element}
+		column, err := ep.identifier()
+		if err != nil {
+			return nil // an expression in parentheses
+		}
+
+		columns = append(columns, column)
+	}
+
+	for _, t := range p.tokens[p.pos:] {
+		if t.is("where") {
+			return nil
+		}
+	}
+
+	if indexName == "" {
+		indexName = table.Name + "_" + strings.Join(columns, "_") + "_idx"
+	}
+
+	table.UniqueConstraints = append(table.UniqueConstraints, UniqueConstraint{
+		TableSchema:    table.Schema,
+		TableName:      table.Name,
+		ConstraintName: indexName,
+		ColumnNames:    columns,
+	})
+
+	return nil
+}
+
+func (p *parser) alterTable(schema *Schema) error {
+	p.accept("if", "exists")
+	p.accept("only")
+
+	schemaName, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	table := schema.Table(schemaName + "." + name)
+	if table == nil {
+		return errors.New("ALTER TABLE references unknown table " + name)
+	}
+
+	for _, action := range split(p.tokens[p.pos:], ",") {
+		ap := &parser{src: p.src, tokens: action}
+		if !ap.accept("add") {
+			continue
+		}
+
+		if ap.isTableConstraint() {
+			err = ap.tableConstraint(schema, table)
+		} else {
+			ap.accept("column")
+			ap.accept("if", "not", "exists")
+			err = ap.column(schema, table)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) isTableConstraint() bool {
+	t := p.peek()
+	return t.is("constraint") || t.is("primary") || t.is("unique") || t.is("foreign") || t.is("check") || t.is("exclude")
+}
+
+func (p *parser) tableConstraint(schema *Schema, table *Table) error {
+	var constraintName string
+	if p.accept("constraint") {
+		name, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		constraintName = name
+	}
+
+	switch {
+	case p.accept("primary", "key"):
+		columns, err := p.identifierList()
+		if err != nil {
+			return err
+		}
+
+		table.PrimaryKey = columns
+		for _, name := range columns {
+			setNotNull(table, name)
+		}
+
+		addUniqueConstraint(table, constraintName, columns, true)
+	case p.accept("unique"):
+		p.accept("nulls", "not", "distinct")
+		p.accept("nulls", "distinct")
+		columns, err := p.identifierList()
+		if err != nil {
+			return err
+		}
+
+		addUniqueConstraint(table, constraintName, columns, false)
+	case p.accept("check"):
+		definition, err := p.group()
+		if err != nil {
+			return err
+		}
+
+		if constraintName == "" {
+			constraintName = table.Name + "_check"
+		}
+
+		addCheckConstraint(table, constraintName, definition)
+	case p.accept("foreign", "key"):
+		columns, err := p.identifierList()
+		if err != nil {
+			return err
+		}
+
+		if constraintName == "" {
+			constraintName = table.Name + "_" + strings.Join(columns, "_") + "_fkey"
+		}
+
+		return p.references(schema, table, constraintName, columns)
+	}
+
+	return nil
+}
+
+// references reads the REFERENCES clause of a FK constraint
+func (p *parser) references(schema *Schema, table *Table, constraintName string, columns []string) error {
+	err := p.expect("references")
+	if err != nil {
+		return err
+	}
+
+	foreignSchema, foreignTable, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	var foreignColumns []string
+	if p.peek().is("(") {
+		foreignColumns, err = p.identifierList()
+		if err != nil {
+			return err
+		}
+
+		if len(foreignColumns) != len(columns) {
+			return errors.New("FK constraint '" + constraintName + "' has a different number of referencing and referenced columns")
+		}
+	}
+
+	for i, name := range columns {
+		fk := ForeignKeyRelation{
+			TableSchema:        table.Schema,
+			ConstraintName:     constraintName,
+			TableName:          table.Name,
+			ColumnName:         name,
+			ForeignTableSchema: foreignSchema,
+			ForeignTableName:   foreignTable,
+		}
+
+		// Left empty until we know the referenced table's primary key
+		if foreignColumns != nil {
+			fk.ForeignColumnName = foreignColumns[i]
+		}
+
+		schema.ForeignKeys = append(schema.ForeignKeys, fk)
+	}
+
+	// Skip over the referential actions
+	for !p.done() {
+		switch {
+		case p.accept("on", "delete"), p.accept("on", "update"):
+			switch {
+			case p.accept("set", "null"), p.accept("set", "default"):
+				if p.peek().is("(") {
+					_, err = p.identifierList()
+					if err != nil {
+						return err
+					}
+				}
+			case p.accept("no", "action"), p.accept("restrict"), p.accept("cascade"):
+			default:
+				return errors.New("Unknown referential action \"" + p.peek().text + "\" in schema")
+			}
+		case p.accept("match", "full"), p.accept("match", "partial"), p.accept("match", "simple"):
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) column(schema *Schema, table *Table) error {
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+
+	c := Column{
+		Name:              name,
+		OrdinalPosition:   len(table.Columns) + 1,
+		IsNullable:        "YES",
+		IsSelfReferencing: "NO",
+		IsIdentity:        "NO",
+		IsUpdateable:      "YES",
+	}
+
+	serial, err := p.columnType(&c)
+	if err != nil {
+		return err
+	}
+
+	if serial {
+		c.IsNullable = "NO"
+		c.ColumnDefault = sql.NullString{String: "nextval('" + table.Name + "_" + name + "_seq'::regclass)", Valid: true}
+	}
+
+	table.Columns = append(table.Columns, c)
+	col := &table.Columns[len(table.Columns)-1]
+
+	for !p.done() {
+		var constraintName string
+		if p.accept("constraint") {
+			constraintName, err = p.identifier()
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case p.accept("not", "null"):
+			col.IsNullable = "NO"
+		case p.accept("null"):
+			col.IsNullable = "YES"
+		case p.accept("default"):
+			col.ColumnDefault = sql.NullString{String: p.expression(), Valid: true}
+		case p.accept("primary", "key"):
+			col.IsNullable = "NO"
+			table.PrimaryKey = []string{name}
+			addUniqueConstraint(table, constraintName, []string{name}, true)
+		case p.accept("unique"):
+			p.accept("nulls", "not", "distinct")
+			p.accept("nulls", "distinct")
+			addUniqueConstraint(table, constraintName, []string{name}, false)
+		case p.accept("check"):
+			var definition string
+			definition, err = p.group()
+			if constraintName == "" {
+				constraintName = table.Name + "_" + name + "_check"
+			}
+			addCheckConstraint(table, constraintName, definition)
+		case p.accept("collate"):
+			_, _, err = p.qualifiedName()
+		case p.accept("generated", "always", "as", "identity"):
+			err = p.identity(col, "ALWAYS")
+		case p.accept("generated", "by", "default", "as", "identity"):
+			err = p.identity(col, "BY DEFAULT")
+		case p.accept("generated", "always", "as"):
+			_, err = p.group()
+			p.accept("stored")
+		case p.peek().is("references"):
+			if constraintName == "" {
+				constraintName = table.Name + "_" + name + "_fkey"
+			}
+			err = p.references(schema, table, constraintName, []string{name})
+		case p.accept("deferrable"), p.accept("not", "deferrable"),
+			p.accept("initially", "deferred"), p.accept("initially", "immediate"):
+		default:
+			return errors.New("Unexpected \"" + p.peek().text + "\" in definition of column '" + name + "'")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) identity(col *Column, generation string) error {
+	col.IsIdentity = "YES"
+	col.IsNullable = "NO"
+	col.IdentityGeneration = sql.NullString{String: generation, Valid: true}
+	col.IdentityStart = sql.NullInt32{Int32: 1, Valid: true}
+	col.IdentityIncrement = sql.NullInt32{Int32: 1, Valid: true}
+
+	if !p.accept("(") {
+		return nil
+	}
+
+	for !p.accept(")") {
+		if p.done() {
+			return errors.New("Unbalanced parentheses in identity of column '" + col.Name + "'")
+		}
+
+		switch {
+		case p.accept("start"):
+			p.accept("with")
+			n, err := strconv.Atoi(p.signedNumber())
+			if err != nil {
+				return err
+			}
+			col.IdentityStart.Int32 = int32(n)
+		case p.accept("increment"):
+			p.accept("by")
+			n, err := strconv.Atoi(p.signedNumber())
+			if err != nil {
+				return err
+			}
+			col.IdentityIncrement.Int32 = int32(n)
+		default:
+			p.pos += 1
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) signedNumber() string {
+	sign := ""
+	if p.accept("-") {
+		sign = "-"
+	}
+
+	t := p.peek()
+	p.pos += 1
+	return sign + t.text
+}
+
+// expression consumes a DEFAULT expression, which runs until the next
+// column constraint
+func (p *parser) expression() string {
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		t := p.peek()
+		if t.is("(") {
+			depth += 1
+		} else if t.is(")") {
+			depth -= 1
+		} else if depth == 0 && p.pos > start && isColumnConstraint(t) {
+			break
+		}
+
+		p.pos += 1
+	}
+
+	if p.pos == start {
+		return ""
+	}
+
+	return p.src[p.tokens[start].start:p.tokens[p.pos-1].end]
+}
+
+func isColumnConstraint(t token) bool {
+	for _, keyword := range []string{"constraint", "not", "null", "default", "primary", "unique",
+		"check", "collate", "generated", "references", "deferrable", "initially"} {
+		if t.is(keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// columnType reads a column's type into the same fields information_schema
+// would report for it, returning whether it was one of the serial types
+func (p *parser) columnType(c *Column) (bool, error) {
+	var words []string
+	var args []int
+	dimensions := 0
+
+typeLoop:
+	for !p.done() {
+		t := p.peek()
+		switch {
+		case t.is("(") && len(words) > 0:
+			p.pos += 1
+			for !p.accept(")") {
+				if p.accept(",") {
+					continue
+				}
+
+				n, err := strconv.Atoi(p.signedNumber())
+				if err != nil {
+					return false, errors.New("Invalid type modifier on column '" + c.Name + "'")
+				}
+				args = append(args, n)
+			}
+		case t.is("["):
+			p.pos += 1
+			for !p.accept("]") {
+				p.pos += 1
+			}
+			dimensions += 1
+		case t.is("array") && len(words) > 0:
+			p.pos += 1
+			dimensions += 1
+		case t.is(".") && len(words) > 0:
+			p.pos += 1
+			c.UdtSchema = strings.Join(words, " ")
+			words = words[:0]
+		case (t.kind == tokenIdentifier || t.kind == tokenQuotedIdentifier) && !isColumnConstraint(t):
+			name, _ := p.identifier()
+			words = append(words, name)
+		default:
+			break typeLoop
+		}
+	}
+
+	if len(words) == 0 {
+		return false, errors.New("Column '" + c.Name + "' is missing a type")
+	}
+
+	typeName := strings.Join(words, " ")
+	serial := false
+	switch typeName {
+	case "serial", "serial4":
+		typeName, serial = "integer", true
+	case "bigserial", "serial8":
+		typeName, serial = "bigint", true
+	case "smallserial", "serial2":
+		typeName, serial = "smallint", true
+	case "float":
+		typeName = "double precision"
+		if len(args) > 0 && args[0] <= 24 {
+			typeName = "real"
+		}
+		args = nil
+	}
+
+	if alias, ok := typeAliases[typeName]; ok {
+		typeName = alias
+	}
+
+	udt, ok := udtNames[typeName]
+	if ok {
+		c.DataType = typeName
+		c.UdtName = udt
+	} else {
+		c.DataType = "USER-DEFINED"
+		c.UdtName = typeName
+	}
+
+	arg := func(i int) (sql.NullInt32, bool) {
+		if i < len(args) {
+			return sql.NullInt32{Int32: int32(args[i]), Valid: true}, true
+		}
+		return sql.NullInt32{}, false
+	}
+
+	switch c.DataType {
+	case "character varying", "bit varying":
+		c.CharacterMaximumLength, _ = arg(0)
+	case "character", "bit":
+		var given bool
+		c.CharacterMaximumLength, given = arg(0)
+		if !given {
+			c.CharacterMaximumLength = sql.NullInt32{Int32: 1, Valid: true}
+		}
+	case "numeric":
+		c.NumericPrecisionRadix = sql.NullInt32{Int32: 10, Valid: true}
+		c.NumericPrecision, _ = arg(0)
+		if c.NumericPrecision.Valid {
+			var given bool
+			c.NumericScale, given = arg(1)
+			if !given {
+				c.NumericScale = sql.NullInt32{Int32: 0, Valid: true}
+			}
+		}
+	case "smallint", "integer", "bigint", "real", "double precision":
+		c.NumericPrecision = sql.NullInt32{Int32: binaryPrecision[c.DataType], Valid: true}
+		c.NumericPrecisionRadix = sql.NullInt32{Int32: 2, Valid: true}
+		if c.DataType != "real" && c.DataType != "double precision" {
+			c.NumericScale = sql.NullInt32{Int32: 0, Valid: true}
+		}
+	case "timestamp without time zone", "timestamp with time zone", "time without time zone",
+		"time with time zone", "interval":
+		precision, given := arg(0)
+		if !given {
+			precision.Int32 = 6
+		}
+		c.DatetimePrecision = sql.NullInt16{Int16: int16(precision.Int32), Valid: true}
+	case "date":
+		c.DatetimePrecision = sql.NullInt16{Int16: 0, Valid: true}
+	}
+
+	if dimensions > 0 {
+		c.DataType = "ARRAY"
+		c.UdtName = "_" + c.UdtName
+		c.ArrayDimensions = dimensions
+	}
+
+	return serial, nil
+}
+
+// addUniqueConstraint adds a primary key or unique constraint to the table,
+// naming it the same way Postgres would when it wasn't given a name
+func addUniqueConstraint(table *Table, constraintName string, columns []string, primaryKey bool) {
+	if constraintName == "" {
+		if primaryKey {
+			constraintName = table.Name + "_pkey"
+		} else {
+			constraintName = table.Name + "_" + strings.Join(columns, "_") + "_key"
+		}
+	}
+
+	table.UniqueConstraints = append(table.UniqueConstraints, UniqueConstraint{
+		TableSchema:    table.Schema,
+		TableName:      table.Name,
+		ConstraintName: constraintName,
+		ColumnNames:    columns,
+		IsPrimaryKey:   primaryKey,
+	})
+}
+
+func addCheckConstraint(table *Table, constraintName, definition string) {
+	table.CheckConstraints = append(table.CheckConstraints, CheckConstraint{
+		TableSchema:    table.Schema,
+		TableName:      table.Name,
+		ConstraintName: constraintName,
+		Definition:     definition,
+	})
+}
+
+func setNotNull(table *Table, name string) {
+	for i := range table.Columns {
+		if table.Columns[i].Name == name {
+			table.Columns[i].IsNullable = "NO"
+		}
+	}
+}
+
+var typeAliases = map[string]string{
+	"int":          "integer",
+	"int4":         "integer",
+	"int2":         "smallint",
+	"int8":         "bigint",
+	"float4":       "real",
+	"float8":       "double precision",
+	"decimal":      "numeric",
+	"bool":         "boolean",
+	"varchar":      "character varying",
+	"char":         "character",
+	"bpchar":       "character",
+	"char varying": "character varying",
+	"varbit":       "bit varying",
+	"timestamp":    "timestamp without time zone",
+	"timestamptz":  "timestamp with time zone",
+	"time":         "time without time zone",
+	"timetz":       "time with time zone",
+}
+
+var udtNames = map[string]string{
+	"smallint":                    "int2",
+	"integer":                     "int4",
+	"bigint":                      "int8",
+	"real":                        "float4",
+	"double precision":            "float8",
+	"numeric":                     "numeric",
+	"money":                       "money",
+	"boolean":                     "bool",
+	"text":                        "text",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"name":                        "name",
+	"bytea":                       "bytea",
+	"bit":                         "bit",
+	"bit varying":                 "varbit",
+	"date":                        "date",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"interval":                    "interval",
+	"uuid":                        "uuid",
+	"json":                        "json",
+	"jsonb":                       "jsonb",
+	"xml":                         "xml",
+	"inet":                        "inet",
+	"cidr":                        "cidr",
+	"macaddr":                     "macaddr",
+	"macaddr8":                    "macaddr8",
+	"point":                       "point",
+	"line":                        "line",
+	"lseg":                        "lseg",
+	"box":                         "box",
+	"path":                        "path",
+	"polygon":                     "polygon",
+	"circle":                      "circle",
+	"tsvector":                    "tsvector",
+	"tsquery":                     "tsquery",
+	"oid":                         "oid",
+	"pg_lsn":                      "pg_lsn",
+	"int4range":                   "int4range",
+	"int8range":                   "int8range",
+	"numrange":                    "numrange",
+	"tsrange":                     "tsrange",
+	"tstzrange":                   "tstzrange",
+	"daterange":                   "daterange",
+}
+
+var binaryPrecision = map[string]int32{
+	"smallint":         16,
+	"integer":          32,
+	"bigint":           64,
+	"real":             24,
+	"double precision": 53,
+}
@@ -0,0 +1,237 @@
+package ddl
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const schemaSql = `
+-- Example schema
+CREATE SCHEMA testing;
+
+CREATE TABLE testing.todos (
+    id INT GENERATED ALWAYS AS IDENTITY (START WITH 10 INCREMENT BY 5),
+    task TEXT NOT NULL,
+    complete BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP
+);
+
+CREATE TABLE users (
+    id bigserial PRIMARY KEY,
+    email character varying(120) NOT NULL UNIQUE,
+    balance numeric(10, 2) DEFAULT 0 CHECK (balance >= 0),
+    tags text[],
+    "Display Name" varchar(40)
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    author_id bigint NOT NULL REFERENCES users ON DELETE SET NULL,
+    editor_id bigint,
+    published_at timestamp(3) with time zone,
+    CONSTRAINT posts_editor_fk FOREIGN KEY (editor_id) REFERENCES users (id)
+);
+
+CREATE INDEX posts_author_idx ON posts (author_id);
+
+ALTER TABLE ONLY testing.todos ADD COLUMN post_id uuid;
+ALTER TABLE testing.todos ADD CONSTRAINT todos_post_fk FOREIGN KEY (post_id) REFERENCES posts (id);
+`
+
+func TestParseColumns(t *testing.T) {
+	schema, err := Parse(schemaSql)
+	if err != nil {
+		t.Fatalf("Error calling Parse: %s", err)
+	}
+
+	cases := map[string]string{
+		"todos": "id:integer:int4:NO,task:text:text:NO,complete:boolean:bool:NO,created_at:timestamp without time zone:timestamp:YES,post_id:uuid:uuid:YES",
+		"users": "id:bigint:int8:NO,email:character varying:varchar:NO,balance:numeric:numeric:YES,tags:ARRAY:_text:YES,Display Name:character varying:varchar:YES",
+		"posts": "id:uuid:uuid:NO,author_id:bigint:int8:NO,editor_id:bigint:int8:YES,published_at:timestamp with time zone:timestamptz:YES",
+	}
+
+	for name, expected := range cases {
+		table := schema.Table(name)
+		if table == nil {
+			t.Fatalf("Table %s was not parsed", name)
+		}
+
+		var actual []string
+		for _, col := range table.Columns {
+			actual = append(actual, col.Name+":"+col.DataType+":"+col.UdtName+":"+col.IsNullable)
+		}
+
+		if strings.Join(actual, ",") != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, ","))
+		}
+	}
+
+	todos := schema.Table("testing.todos")
+	id := todos.Columns[0]
+	if id.IsIdentity != "YES" || id.IdentityGeneration.String != "ALWAYS" || id.IdentityStart.Int32 != 10 || id.IdentityIncrement.Int32 != 5 {
+		t.Errorf("Expected todos.id to be an identity column starting at 10 in steps of 5")
+	}
+
+	if todos.Columns[2].ColumnDefault.String != "false" {
+		t.Errorf("Expected todos.complete to default to false, got %s", todos.Columns[2].ColumnDefault.String)
+	}
+
+	users := schema.Table("public.users")
+	if users.Columns[0].ColumnDefault.String != "nextval('users_id_seq'::regclass)" {
+		t.Errorf("Expected users.id to default to its sequence, got %s", users.Columns[0].ColumnDefault.String)
+	}
+
+	if users.Columns[1].CharacterMaximumLength.Int32 != 120 {
+		t.Errorf("Expected users.email to have a maximum length of 120")
+	}
+
+	if users.Columns[2].NumericPrecision.Int32 != 10 || users.Columns[2].NumericScale.Int32 != 2 {
+		t.Errorf("Expected users.balance to have a precision of 10 and scale of 2")
+	}
+
+	if len(users.CheckConstraints) != 1 || users.CheckConstraints[0].ConstraintName != "users_balance_check" || users.CheckConstraints[0].Definition != "balance >= 0" {
+		t.Errorf("Expected users.balance to have a CHECK constraint of balance >= 0, got %v", users.CheckConstraints)
+	}
+}
+
+func TestParseForeignKeys(t *testing.T) {
+	schema, err := Parse(schemaSql)
+	if err != nil {
+		t.Fatalf("Error calling Parse: %s", err)
+	}
+
+	var actual []string
+	for _, fk := range schema.ForeignKeys {
+		actual = append(actual, fk.ConstraintName+":"+fk.TableName+"."+fk.ColumnName+"->"+fk.ForeignTableName+"."+fk.ForeignColumnName)
+	}
+
+	expected := "posts_author_id_fkey:posts.author_id->users.id,posts_editor_fk:posts.editor_id->users.id,todos_post_fk:todos.post_id->posts.id"
+	if strings.Join(actual, ",") != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, ","))
+	}
+}
+
+func TestParseUniqueConstraints(t *testing.T) {
+	schema, err := Parse(schemaSql + `
+CREATE TABLE memberships (
+    user_id bigint,
+    team_id bigint,
+    CONSTRAINT memberships_key PRIMARY KEY (user_id, team_id)
+);
+
+CREATE UNIQUE INDEX users_name_idx ON users ("Display Name");
+CREATE UNIQUE INDEX users_lower_email_idx ON users (lower(email));
+CREATE UNIQUE INDEX posts_editor_idx ON posts (editor_id) WHERE editor_id IS NOT NULL;
+`)
+	if err != nil {
+		t.Fatalf("Error calling Parse: %s", err)
+	}
+
+	cases := map[string]string{
+		"users":       "users_pkey:id:true users_email_key:email:false users_name_idx:Display Name:false",
+		"posts":       "posts_pkey:id:true",
+		"memberships": "memberships_key:user_id,team_id:true",
+	}
+
+	for name, expected := range cases {
+		var actual []string
+		for _, constraint := range schema.Table(name).UniqueConstraints {
+			actual = append(actual, constraint.ConstraintName+":"+strings.Join(constraint.ColumnNames, ",")+":"+strconv.FormatBool(constraint.IsPrimaryKey))
+		}
+
+		if strings.Join(actual, " ") != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, " "))
+		}
+	}
+}
+
+func TestParseEnums(t *testing.T) {
+	schema, err := Parse(`
+CREATE TABLE tickets (
+    id serial PRIMARY KEY,
+    status ticket_status NOT NULL,
+    history public.ticket_status[][]
+);
+
+CREATE TYPE public.ticket_status AS ENUM ('open', 'in progress', 'won''t fix');
+CREATE TYPE point3d AS (x float, y float, z float);
+`)
+	if err != nil {
+		t.Fatalf("Error calling Parse: %s", err)
+	}
+
+	status := schema.Table("tickets").Columns[1]
+	if status.DataType != "USER-DEFINED" || status.UdtName != "ticket_status" {
+		t.Errorf("Expected tickets.status to be a ticket_status, got %s (%s)", status.DataType, status.UdtName)
+	}
+
+	expected := "open,in progress,won't fix"
+	if strings.Join(status.EnumValues, ",") != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(status.EnumValues, ","))
+	}
+
+	history := schema.Table("tickets").Columns[2]
+	if history.DataType != "ARRAY" || history.UdtSchema != "public" || history.UdtName != "_ticket_status" || history.ArrayDimensions != 2 {
+		t.Errorf("Expected tickets.history to be a 2 dimensional array of public.ticket_status")
+	}
+
+	if strings.Join(history.EnumValues, ",") != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(history.EnumValues, ","))
+	}
+}
+
+func TestParseCheck(t *testing.T) {
+	cases := map[string]string{
+		"price > 0":                      "price > 0",
+		"CHECK ((price > (0)::numeric))": "price > 0",
+		"0 <= qty AND qty < 10":          "qty >= 0,qty < 10",
+		"qty BETWEEN -1 AND 5":           "qty >= -1,qty <= 5",
+		"status IN ('a', 'won''t')":      "status IN 'a'|'won''t'",
+		"CHECK ((status = ANY (ARRAY['a'::text, 'b'::text]))) NOT VALID": "status IN 'a'|'b'",
+		"char_length(code) = 3":                     "length(code) = 3",
+		"CHECK ((ends_at > starts_at))":             "ends_at > other(starts_at)",
+		"(`price` != 0)":                            "price <> 0",
+		"\"Name\" IS NOT NULL":                      "Name IS NOT NULL",
+		"CHECK ((sku ~ '^[A-Z]{3}-\\d{4}$'::text))": "sku ~ '^[A-Z]{3}-\\d{4}$'",
+		"(`code` regexp _utf8mb4'^[a-z]+$')":        "code ~ '^[a-z]+$'",
+		"plate ~* '^[a-z]{2}[0-9]{3}$'":             "plate ~* '^[a-z]{2}[0-9]{3}$'",
+	}
+
+	for definition, expected := range cases {
+		conditions, err := ParseCheck(definition)
+		if err != nil {
+			t.Errorf("Error calling ParseCheck(%s): %s", definition, err)
+			continue
+		}
+
+		var actual []string
+		for _, c := range conditions {
+			column := c.ColumnName
+			if c.Length {
+				column = "length(" + column + ")"
+			}
+
+			value := c.Value
+			switch {
+			case c.OtherColumnName != "":
+				value = "other(" + c.OtherColumnName + ")"
+			case c.Values != nil:
+				value = strings.Join(c.Values, "|")
+			}
+
+			actual = append(actual, strings.TrimSpace(column+" "+c.Operator+" "+value))
+		}
+
+		if strings.Join(actual, ",") != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, ","))
+		}
+	}
+
+	for _, definition := range []string{"name ~ other_name", "'^a' ~ name", "a > 0 OR b > 0", "qty NOT BETWEEN 1 AND 5"} {
+		_, err := ParseCheck(definition)
+		if err == nil {
+			t.Errorf("Expected an error calling ParseCheck(%s)", definition)
+		}
+	}
+}
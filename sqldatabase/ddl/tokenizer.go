@@ -0,0 +1,168 @@
+package ddl
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenIdentifier tokenKind = iota
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// is checks if the token is the given (case insensitive) keyword or symbol
+func (t token) is(text string) bool {
+	switch t.kind {
+	case tokenIdentifier:
+		return strings.EqualFold(t.text, text)
+	case tokenSymbol:
+		return t.text == text
+	default:
+		return false
+	}
+}
+
+// tokenize splits SQL source into tokens, dropping whitespace and comments.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		start := i
+
+		switch {
+		case unicode.IsSpace(c):
+			i += 1
+		case strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 1
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("Unterminated comment in schema")
+			}
+			i += end + 4
+		case c == '\'' || ((c == 'E' || c == 'e') && i+1 < len(src) && src[i+1] == '\''):
+			if c != '\'' {
+				i += 1
+			}
+
+			var value strings.Builder
+			i += 1
+			for {
+				if i >= len(src) {
+					return nil, errors.New("Unterminated string in schema")
+				}
+
+				if src[i] == '\'' {
+					if i+1 < len(src) && src[i+1] == '\'' {
+						value.WriteByte('\'') // escaped single quote
+						i += 2
+						continue
+					}
+
+					i += 1
+					break
+				}
+
+				value.WriteByte(src[i])
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), start: start, end: i})
+		case c == '"' || c == '`':
+			// MySQL quotes identifiers with backticks
+			quote := src[i]
+			var value strings.Builder
+			i += 1
+			for {
+				if i >= len(src) {
+					return nil, errors.New("Unterminated quoted identifier in schema")
+				}
+
+				if src[i] == quote {
+					if i+1 < len(src) && src[i+1] == quote {
+						value.WriteByte(quote) // escaped quote
+						i += 2
+						continue
+					}
+
+					i += 1
+					break
+				}
+
+				value.WriteByte(src[i])
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdentifier, text: value.String(), start: start, end: i})
+		case c == '$' && dollarTag(src[i:]) != "":
+			// Dollar quoted strings show up in function bodies
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				return nil, errors.New("Unterminated dollar quoted string in schema")
+			}
+			text := src[i+len(tag) : i+len(tag)+end]
+			i += len(tag) + end + len(tag)
+			tokens = append(tokens, token{kind: tokenString, text: text, start: start, end: i})
+		case unicode.IsLetter(c) || c == '_':
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_' || src[i] == '$') {
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: src[start:i], start: start, end: i})
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.' || src[i] == 'e' || src[i] == 'E') {
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[start:i], start: start, end: i})
+		default:
+			// Keep multi-character operators together
+			for _, op := range []string{"::", "<=", ">=", "<>", "!=", "||", "!~*", "!~", "~*"} {
+				if strings.HasPrefix(src[i:], op) {
+					i += len(op)
+					break
+				}
+			}
+
+			if i == start {
+				i += 1
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: src[start:i], start: start, end: i})
+		}
+	}
+
+	return tokens, nil
+}
+
+// dollarTag returns the opening tag of a dollar quoted string such as $$ or
+// $body$, or an empty string when the text doesn't start with one.
+func dollarTag(text string) string {
+	for i := 1; i < len(text); i++ {
+		if text[i] == '$' {
+			return text[:i+1]
+		}
+
+		if !unicode.IsLetter(rune(text[i])) && text[i] != '_' {
+			return ""
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package drivers
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+
+	"dummy/sqldatabase/ddl"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
+)
+
+// DdlDriver reads the tables from a schema file instead of a live database
+// so that data can be generated offline. The schema is expected to be
+// written for Postgres and the output follows suit.
+type DdlDriver struct {
+	schema *ddl.Schema
+}
+
+func NewDdlDriver(path string) (*DdlDriver, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := ddl.Parse(string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DdlDriver{schema: schema}, nil
+}
+
+// Database returns nil since there's no database behind the schema file
+func (dd *DdlDriver) Database() *sql.DB {
+	return nil
+}
+
+func (dd *DdlDriver) TableColumns(schemaName, tableName string) ([]Column, error) {
+	t := dd.schema.Table(schemaName + "." + tableName)
+	if t == nil {
+		return make([]Column, 0), errors.New("Table " + schemaName + "." + tableName + " is not defined in the schema")
+	}
+
+	columns := make([]Column, len(t.Columns))
+	copy(columns, t.Columns)
+	return columns, nil
+}
+
+// ColumnValues returns no values since the schema file has no rows
+func (dd *DdlDriver) ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error) {
+	return make([][]string, 0), nil
+}
+
+func (dd *DdlDriver) UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error) {
+	t := dd.schema.Table(schemaName + "." + tableName)
+	if t == nil {
+		return make([]UniqueConstraint, 0), errors.New("Table " + schemaName + "." + tableName + " is not defined in the schema")
+	}
+
+	return t.UniqueConstraints, nil
+}
+
+func (dd *DdlDriver) CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error) {
+	t := dd.schema.Table(schemaName + "." + tableName)
+	if t == nil {
+		return make([]CheckConstraint, 0), errors.New("Table " + schemaName + "." + tableName + " is not defined in the schema")
+	}
+
+	return t.CheckConstraints, nil
+}
+
+func (dd *DdlDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelation, error) {
+	fkMapping := make(map[string][]ForeignKeyRelation)
+	for _, fk := range dd.schema.ForeignKeys {
+		fkMapping[fk.QualifiedTableName()] = append(fkMapping[fk.QualifiedTableName()], fk)
+	}
+
+	return fkMapping, nil
+}
+
+func (dd *DdlDriver) InsertStatement(t *Table) string {
+	return (&PostgresqlDriver{}).InsertStatement(t)
+}
+
+func (dd *DdlDriver) CopyStatement(t *Table) (string, error) {
+	return (&PostgresqlDriver{}).CopyStatement(t)
+}
+
+func (dd *DdlDriver) SequenceStatements(t *Table) []string {
+	return (&PostgresqlDriver{}).SequenceStatements(t)
+}
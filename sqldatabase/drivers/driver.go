@@ -3,14 +3,36 @@ package drivers
 import (
 	"database/sql"
 
+	. "dummy/sqldatabase/checkconstraint"
 	. "dummy/sqldatabase/column"
 	. "dummy/sqldatabase/foreignkeyrelation"
 	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
 )
 
+// maxColumnValues limits how many existing rows are read back from a table
+// when looking for values to reference
+const maxColumnValues = 10000
+
 type SqlDatabaseDriver interface {
 	Database() *sql.DB
 	ForeignKeyRelations() (map[string][]ForeignKeyRelation, error)
-	TableColumns(tableName string) ([]Column, error)
+	TableColumns(schemaName, tableName string) ([]Column, error)
+	ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error)
+	UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error)
+	CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error)
 	InsertStatement(table *Table) string
+	SequenceStatements(table *Table) []string
+}
+
+// CopyDriver is implemented by the drivers that can write the generated rows
+// of a table as a COPY block, which loads much faster than an INSERT
+type CopyDriver interface {
+	CopyStatement(table *Table) (string, error)
+}
+
+// MultiInsertDriver is implemented by the drivers that can need more than one
+// INSERT for a batch of rows, which InsertStatement joins together
+type MultiInsertDriver interface {
+	InsertStatements(table *Table) []string
 }
@@ -0,0 +1,453 @@
+package drivers
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
+)
+
+type MySQLDriver struct {
+	database *sql.DB
+
+	// schemas are the databases foreign keys are looked for in, or only
+	// the connected database when empty
+	schemas []string
+}
+
+func NewMySQLDriver(user, password, host, name string, schemas []string) (*MySQLDriver, error) {
+	conn := fmt.Sprintf("%s:%s@tcp(%s)/%s", user, password, host, name)
+	db, err := sql.Open("mysql", conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQLDriver{database: db, schemas: schemas}, nil
+}
+
+func (md *MySQLDriver) Database() *sql.DB {
+	return md.database
+}
+
+func (md *MySQLDriver) TableColumns(schemaName, tableName string) ([]Column, error) {
+	var columns []Column
+
+	rows, err := md.Database().Query(`
+		SELECT column_name, ordinal_position, column_default, is_nullable, data_type,
+		character_maximum_length, character_octet_length, numeric_precision,
+		numeric_scale, datetime_precision, column_type, extra
+			FROM information_schema.columns
+			WHERE table_schema = ? AND table_name = ?
+			ORDER BY ordinal_position`,
+		schemaName, tableName,
+	)
+
+	if err != nil {
+		return make([]Column, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c Column
+		var maximumLength, octetLength, precision, scale, datetimePrecision sql.NullInt64
+		var dataType, columnType, extra string
+
+		err := rows.Scan(&c.Name, &c.OrdinalPosition, &c.ColumnDefault, &c.IsNullable,
+			&dataType, &maximumLength, &octetLength, &precision, &scale,
+			&datetimePrecision, &columnType, &extra)
+
+		if err != nil {
+			return make([]Column, 0), err
+		}
+
+		// The lengths of the larger text and blob types don't fit in our columns
+		c.CharacterMaximumLength = clampNullInt32(maximumLength)
+		c.CharacterOctetLength = clampNullInt32(octetLength)
+		c.NumericPrecision = clampNullInt32(precision)
+		c.NumericScale = clampNullInt32(scale)
+		c.DatetimePrecision = sql.NullInt16{Int16: int16(datetimePrecision.Int64), Valid: datetimePrecision.Valid}
+
+		c.DataType = mysqlToDatatype(dataType, columnType)
+		c.UdtName = columnType
+		c.IsSelfReferencing = "NO"
+		c.IsUpdateable = "YES"
+
+		if strings.Contains(extra, "auto_increment") {
+			c.IsIdentity = "YES"
+		} else {
+			c.IsIdentity = "NO"
+		}
+
+		// MySQL reports the length of bit(n) as its precision
+		if dataType == "bit" {
+			c.CharacterMaximumLength = c.NumericPrecision
+		}
+
+		if dataType == "enum" || dataType == "set" {
+			c.EnumValues = mysqlEnumValues(columnType)
+		}
+
+		columns = append(columns, c)
+	}
+
+	return columns, nil
+}
+
+// ColumnValues returns the distinct non-null values stored in the given
+// columns of a table, ordered from largest to smallest.
+func (md *MySQLDriver) ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error) {
+	var values [][]string
+
+	var columns, conditions, ordering []string
+	for _, name := range columnNames {
+		columns = append(columns, quoteMySQLIdentifier(name))
+		conditions = append(conditions, quoteMySQLIdentifier(name)+" IS NOT NULL")
+		ordering = append(ordering, quoteMySQLIdentifier(name)+" DESC")
+	}
+
+	rows, err := md.Database().Query(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s ORDER BY %s LIMIT %d",
+		strings.Join(columns, ","),
+		quoteMySQLTableName(schemaName, tableName),
+		strings.Join(conditions, " AND "),
+		strings.Join(ordering, ","),
+		maxColumnValues,
+	))
+
+	if err != nil {
+		return make([][]string, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make([]string, len(columnNames))
+		dest := make([]any, len(columnNames))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return make([][]string, 0), err
+		}
+
+		values = append(values, row)
+	}
+
+	return values, nil
+}
+
+// UniqueConstraints returns the primary key and unique indexes of a table.
+// Indexes on expressions are left out.
+func (md *MySQLDriver) UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error) {
+	var constraints []UniqueConstraint
+
+	rows, err := md.Database().Query(`
+		SELECT index_name, column_name
+			FROM information_schema.statistics
+			WHERE table_schema = ? AND table_name = ? AND non_unique = 0
+			AND index_name NOT IN (
+				SELECT index_name
+					FROM information_schema.statistics
+					WHERE table_schema = ? AND table_name = ? AND column_name IS NULL
+			)
+			ORDER BY index_name, seq_in_index`,
+		schemaName, tableName, schemaName, tableName,
+	)
+
+	if err != nil {
+		return make([]UniqueConstraint, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column string
+		err := rows.Scan(&name, &column)
+		if err != nil {
+			return make([]UniqueConstraint, 0), err
+		}
+
+		if len(constraints) == 0 || constraints[len(constraints)-1].ConstraintName != name {
+			constraints = append(constraints, UniqueConstraint{
+				TableSchema:    schemaName,
+				TableName:      tableName,
+				ConstraintName: name,
+				IsPrimaryKey:   name == "PRIMARY",
+			})
+		}
+
+		last := &constraints[len(constraints)-1]
+		last.ColumnNames = append(last.ColumnNames, column)
+	}
+
+	return constraints, nil
+}
+
+func (md *MySQLDriver) CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error) {
+	var constraints []CheckConstraint
+
+	rows, err := md.Database().Query(`
+		SELECT cc.constraint_name, cc.check_clause
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.check_constraints cc
+				ON cc.constraint_schema = tc.constraint_schema
+				AND cc.constraint_name = tc.constraint_name
+			WHERE tc.table_schema = ? AND tc.table_name = ? AND tc.constraint_type = 'CHECK'
+			ORDER BY cc.constraint_name`,
+		schemaName, tableName,
+	)
+
+	if err != nil {
+		return make([]CheckConstraint, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		constraint := CheckConstraint{TableSchema: schemaName, TableName: tableName}
+		err := rows.Scan(&constraint.ConstraintName, &constraint.Definition)
+		if err != nil {
+			return make([]CheckConstraint, 0), err
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
+func (md *MySQLDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelation, error) {
+	fkMapping := make(map[string][]ForeignKeyRelation)
+
+	schemas := "DATABASE()"
+	args := make([]any, 0, len(md.schemas))
+	if len(md.schemas) > 0 {
+		schemas = strings.Repeat("?,", len(md.schemas)-1) + "?"
+		for _, schema := range md.schemas {
+			args = append(args, schema)
+		}
+	}
+
+	// Unlike Postgres, MySQL keeps the referenced columns alongside the
+	// constraint columns so composite keys line up by ordinal position
+	rows, err := md.Database().Query(`
+		SELECT table_schema, constraint_name, table_name, column_name,
+		referenced_table_schema, referenced_table_name, referenced_column_name
+			FROM information_schema.key_column_usage
+			WHERE table_schema IN (`+schemas+`) AND referenced_table_name IS NOT NULL
+			ORDER BY table_name, constraint_name, ordinal_position`,
+		args...,
+	)
+
+	if err != nil {
+		return make(map[string][]ForeignKeyRelation), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var fk ForeignKeyRelation
+		err := rows.Scan(
+			&fk.TableSchema,
+			&fk.ConstraintName,
+			&fk.TableName,
+			&fk.ColumnName,
+			&fk.ForeignTableSchema,
+			&fk.ForeignTableName,
+			&fk.ForeignColumnName,
+		)
+
+		if err != nil {
+			return make(map[string][]ForeignKeyRelation), err
+		}
+
+		fkMapping[fk.QualifiedTableName()] = append(fkMapping[fk.QualifiedTableName()], fk)
+	}
+
+	return fkMapping, nil
+}
+
+func (md *MySQLDriver) InsertStatement(t *Table) string {
+	var output strings.Builder
+
+	output.WriteString("INSERT INTO ")
+	output.WriteString(quoteMySQLTableName(t.Schema, t.Name))
+	output.WriteString(" (")
+
+	// Write out the column names
+	{
+		written := 0
+		for i, col := range t.Columns {
+			// Omitted columns are left for the database to fill in
+			if slices.Contains(t.Metadata.OmittedColumns, i) {
+				continue
+			}
+
+			if written > 0 {
+				output.WriteRune(',')
+			}
+
+			output.WriteString(quoteMySQLIdentifier(col.Name))
+			written += 1
+		}
+	}
+
+	output.WriteString(") VALUES ")
+
+	// Build the main part of the insert statement from the generated data
+	for i := range len(t.InsertRows) {
+		if i > 0 {
+			output.WriteRune(',')
+		}
+
+		// Build the current row
+		{
+			output.WriteRune('(')
+			written := 0
+			for j, value := range t.InsertRows[i] {
+				if slices.Contains(t.Metadata.OmittedColumns, j) {
+					continue
+				}
+
+				if written > 0 {
+					output.WriteRune(',')
+				}
+
+				output.WriteString(mysqlLiteral(value))
+				written += 1
+			}
+
+			output.WriteRune(')')
+		}
+	}
+
+	output.WriteRune(';')
+	return output.String()
+}
+
+// SequenceStatements returns no statements since MySQL moves past the
+// values written to auto_increment columns by itself.
+func (md *MySQLDriver) SequenceStatements(t *Table) []string {
+	return make([]string, 0)
+}
+
+// mysqlLiteral writes a value in the syntax MySQL reads it in. Backslashes
+// escape characters in MySQL strings so they need escaping themselves.
+func mysqlLiteral(value Value) string {
+	switch value := value.(type) {
+	case Marker:
+		return string(value)
+	case bool, int64, Number:
+		return Text(value)
+	case Bits:
+		return "b'" + string(value) + "'"
+	case []byte:
+		return "X'" + hex.EncodeToString(value) + "'"
+	default:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(Text(value)) + "'"
+	}
+}
+
+func quoteMySQLIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteMySQLTableName(schemaName, tableName string) string {
+	if schemaName == "" {
+		return quoteMySQLIdentifier(tableName)
+	}
+
+	return quoteMySQLIdentifier(schemaName) + "." + quoteMySQLIdentifier(tableName)
+}
+
+// mysqlToDatatype maps MySQL's data types onto the datatypes understood by
+// the generators.
+func mysqlToDatatype(dataType, columnType string) string {
+	unsigned := strings.Contains(columnType, "unsigned")
+
+	switch dataType {
+	case "tinyint":
+		// MySQL has no real boolean type and uses tinyint(1) instead
+		if strings.HasPrefix(columnType, "tinyint(1)") {
+			return "boolean"
+		}
+		return "tinyint"
+	case "int", "integer", "bigint":
+		if unsigned {
+			return "serial"
+		} else if dataType == "bigint" {
+			return "bigint"
+		}
+		return "integer"
+	case "decimal":
+		return "numeric"
+	case "float":
+		return "real"
+	case "double":
+		return "double precision"
+	case "char":
+		return "character"
+	case "varchar":
+		return "character varying"
+	case "tinytext", "text", "mediumtext", "longtext",
+		"binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "text"
+	case "datetime":
+		return "timestamp without time zone"
+	case "timestamp":
+		// Unlike datetime, timestamp only holds times from 1970 to 2038
+		return "timestamp"
+	case "time":
+		return "time without time zone"
+	default:
+		return dataType
+	}
+}
+
+// mysqlEnumValues reads the allowed values out of an enum or set
+// column type such as enum('a','b','c').
+func mysqlEnumValues(columnType string) []string {
+	var values []string
+
+	start := strings.IndexRune(columnType, '(')
+	end := strings.LastIndexByte(columnType, ')')
+	if start < 0 || end < start {
+		return values
+	}
+
+	var value strings.Builder
+	quoted := false
+	list := columnType[start+1 : end]
+	for i := 0; i < len(list); i++ {
+		switch {
+		case list[i] == '\'' && quoted && i+1 < len(list) && list[i+1] == '\'':
+			value.WriteByte('\'') // escaped single quote
+			i += 1
+		case list[i] == '\'':
+			quoted = !quoted
+			if !quoted {
+				values = append(values, value.String())
+				value.Reset()
+			}
+		case quoted:
+			value.WriteByte(list[i])
+		}
+	}
+
+	return values
+}
+
+func clampNullInt32(n sql.NullInt64) sql.NullInt32 {
+	return sql.NullInt32{Int32: int32(min(n.Int64, math.MaxInt32)), Valid: n.Valid}
+}
@@ -0,0 +1,57 @@
+package drivers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"dummy/commands"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/value"
+)
+
+func TestToMySQLStatement(t *testing.T) {
+	driver := MySQLDriver{database: nil}
+
+	var tblCmds commands.TableCommands
+	table := createFakeTable("fake_table")
+	table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
+
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Bill Bob", "2025-04-12"})
+	table.InsertRows = append(table.InsertRows, []Value{Default, `Jim \ George`, "2025-04-12"})
+
+	actual := driver.InsertStatement(table)
+	expected := "INSERT INTO `fake_table` (`id`,`name`,`created_at`) VALUES (DEFAULT,'Bill Bob','2025-04-12'),(DEFAULT,'Jim \\\\ George','2025-04-12');"
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestMySQLEnumValues(t *testing.T) {
+	actual := mysqlEnumValues("enum('draft','in review','it''s done')")
+	expected := []string{"draft", "in review", "it's done"}
+
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected:\n%v\n\nGot:\n%v", expected, actual)
+	}
+}
+
+func TestMySQLToDatatype(t *testing.T) {
+	cases := map[string][2]string{
+		"boolean":                     {"tinyint", "tinyint(1)"},
+		"tinyint":                     {"tinyint", "tinyint(4)"},
+		"serial":                      {"int", "int(10) unsigned"},
+		"text":                        {"mediumtext", "mediumtext"},
+		"timestamp without time zone": {"datetime", "datetime"},
+		"timestamp":                   {"timestamp", "timestamp"},
+		"enum":                        {"enum", "enum('a','b')"},
+	}
+
+	for expected, types := range cases {
+		actual := mysqlToDatatype(types[0], types[1])
+		if actual != expected {
+			t.Errorf("mysqlToDatatype(%s, %s): expected %s, got %s", types[0], types[1], expected, actual)
+		}
+	}
+}
@@ -2,13 +2,22 @@ package drivers
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 
+	"github.com/lib/pq"
+
+	"dummy/output"
+
+	. "dummy/sqldatabase/checkconstraint"
 	. "dummy/sqldatabase/column"
 	. "dummy/sqldatabase/foreignkeyrelation"
 	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
 )
 
 type PostgresqlDriver struct {
@@ -29,7 +38,7 @@ func (pd *PostgresqlDriver) Database() *sql.DB {
 	return pd.database
 }
 
-func (pd *PostgresqlDriver) TableColumns(tableName string) ([]Column, error) {
+func (pd *PostgresqlDriver) TableColumns(schemaName, tableName string) ([]Column, error) {
 	var columns []Column
 
 	rows, err := pd.Database().Query(`
@@ -37,11 +46,17 @@ func (pd *PostgresqlDriver) TableColumns(tableName string) ([]Column, error) {
 		character_maximum_length, character_octet_length, numeric_precision,
 		numeric_precision_radix, numeric_scale, datetime_precision, udt_name,
 		is_self_referencing, is_identity, identity_generation, identity_start,
-		identity_increment, identity_maximum, identity_minimum, is_updatable
+		identity_increment, identity_maximum, identity_minimum, is_updatable,
+		udt_schema, COALESCE((
+			SELECT a.attndims
+				FROM pg_catalog.pg_attribute a
+				WHERE a.attrelid = format('%I.%I', table_schema, table_name)::regclass
+				AND a.attname = column_name
+		), 0)
 			FROM information_schema.columns
-			WHERE table_name = $1
+			WHERE table_schema = $1 AND table_name = $2
 			ORDER BY ordinal_position`,
-		tableName,
+		schemaName, tableName,
 	)
 
 	if err != nil {
@@ -58,7 +73,7 @@ func (pd *PostgresqlDriver) TableColumns(tableName string) ([]Column, error) {
 			&c.DatetimePrecision, &c.UdtName, &c.IsSelfReferencing,
 			&c.IsIdentity, &c.IdentityGeneration, &c.IdentityStart,
 			&c.IdentityIncrement, &c.IdentityMaximum, &c.IdentityMinimum,
-			&c.IsUpdateable)
+			&c.IsUpdateable, &c.UdtSchema, &c.ArrayDimensions)
 
 		if err != nil {
 			return make([]Column, 0), err
@@ -67,9 +82,186 @@ func (pd *PostgresqlDriver) TableColumns(tableName string) ([]Column, error) {
 		columns = append(columns, c)
 	}
 
+	// Look up the labels of enum types once the column rows are closed
+	rows.Close()
+	for i := range columns {
+		typeName := columns[i].UdtName
+		switch columns[i].DataType {
+		case "USER-DEFINED":
+		case "ARRAY":
+			// The labels belong to the type of the array's elements
+			typeName = strings.TrimPrefix(typeName, "_")
+		default:
+			continue
+		}
+
+		labels, err := pd.enumLabels(columns[i].UdtSchema, typeName)
+		if err != nil {
+			return make([]Column, 0), err
+		}
+
+		columns[i].EnumValues = labels
+	}
+
 	return columns, nil
 }
 
+// enumLabels returns the labels of an enum type in the order they were
+// declared. Types that aren't enums have no labels.
+func (pd *PostgresqlDriver) enumLabels(schemaName, typeName string) ([]string, error) {
+	var labels []string
+
+	rows, err := pd.Database().Query(`
+		SELECT e.enumlabel
+			FROM pg_catalog.pg_enum e
+			JOIN pg_catalog.pg_type t ON t.oid = e.enumtypid
+			JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+			WHERE n.nspname = $1 AND t.typname = $2
+			ORDER BY e.enumsortorder`,
+		schemaName, typeName,
+	)
+
+	if err != nil {
+		return make([]string, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var label string
+		err := rows.Scan(&label)
+		if err != nil {
+			return make([]string, 0), err
+		}
+
+		labels = append(labels, label)
+	}
+
+	return labels, nil
+}
+
+// ColumnValues returns the distinct non-null values stored in the given
+// columns of a table, ordered from largest to smallest.
+func (pd *PostgresqlDriver) ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error) {
+	var values [][]string
+
+	var columns, conditions, ordering []string
+	for _, name := range columnNames {
+		columns = append(columns, quotePsqlIdentifier(name))
+		conditions = append(conditions, quotePsqlIdentifier(name)+" IS NOT NULL")
+		ordering = append(ordering, quotePsqlIdentifier(name)+" DESC")
+	}
+
+	rows, err := pd.Database().Query(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s ORDER BY %s LIMIT %d",
+		strings.Join(columns, ","),
+		quotePsqlTableName(schemaName, tableName),
+		strings.Join(conditions, " AND "),
+		strings.Join(ordering, ","),
+		maxColumnValues,
+	))
+
+	if err != nil {
+		return make([][]string, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make([]string, len(columnNames))
+		dest := make([]any, len(columnNames))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return make([][]string, 0), err
+		}
+
+		values = append(values, row)
+	}
+
+	return values, nil
+}
+
+// UniqueConstraints returns the primary key, unique constraints and unique
+// indexes of a table. Partial indexes and indexes on expressions are left out.
+func (pd *PostgresqlDriver) UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error) {
+	var constraints []UniqueConstraint
+
+	// Every unique constraint is backed by an index of the same name
+	rows, err := pd.Database().Query(`
+		SELECT c.relname, i.indisprimary, a.attname
+			FROM pg_catalog.pg_index i
+			JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+			JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, position) ON true
+			JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+			WHERE i.indrelid = format('%I.%I', $1, $2)::regclass
+			AND i.indisunique AND i.indpred IS NULL AND i.indexprs IS NULL
+			ORDER BY c.relname, k.position`,
+		schemaName, tableName,
+	)
+
+	if err != nil {
+		return make([]UniqueConstraint, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column string
+		var primaryKey bool
+		err := rows.Scan(&name, &primaryKey, &column)
+		if err != nil {
+			return make([]UniqueConstraint, 0), err
+		}
+
+		if len(constraints) == 0 || constraints[len(constraints)-1].ConstraintName != name {
+			constraints = append(constraints, UniqueConstraint{
+				TableSchema:    schemaName,
+				TableName:      tableName,
+				ConstraintName: name,
+				IsPrimaryKey:   primaryKey,
+			})
+		}
+
+		last := &constraints[len(constraints)-1]
+		last.ColumnNames = append(last.ColumnNames, column)
+	}
+
+	return constraints, nil
+}
+
+func (pd *PostgresqlDriver) CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error) {
+	var constraints []CheckConstraint
+
+	rows, err := pd.Database().Query(`
+		SELECT conname, pg_catalog.pg_get_constraintdef(oid)
+			FROM pg_catalog.pg_constraint
+			WHERE conrelid = format('%I.%I', $1, $2)::regclass AND contype = 'c'
+			ORDER BY conname`,
+		schemaName, tableName,
+	)
+
+	if err != nil {
+		return make([]CheckConstraint, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		constraint := CheckConstraint{TableSchema: schemaName, TableName: tableName}
+		err := rows.Scan(&constraint.ConstraintName, &constraint.Definition)
+		if err != nil {
+			return make([]CheckConstraint, 0), err
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
 func (pd *PostgresqlDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelation, error) {
 	db := pd.Database()
 	fkMapping := make(map[string][]ForeignKeyRelation)
@@ -80,7 +272,7 @@ func (pd *PostgresqlDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelati
 	}
 
 	for _, fk := range fks {
-		table := fk.TableName
+		table := fk.QualifiedTableName()
 		val, ok := fkMapping[table]
 		if !ok {
 			val = make([]ForeignKeyRelation, 0)
@@ -96,23 +288,28 @@ func (pd *PostgresqlDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelati
 func queryForeignKeyRelations(db *sql.DB) ([]ForeignKeyRelation, error) {
 	var fks []ForeignKeyRelation
 
+	// The constraint's columns and the ones they reference are unnested
+	// side by side so that the columns of composite keys are paired up
+	// by position rather than with every column of the other key
 	rows, err := db.Query(`
 		SELECT
-			tc.table_schema, 
-			tc.constraint_name, 
-			tc.table_name, 
-			kcu.column_name, 
-			ccu.table_schema AS foreign_table_schema,
-			ccu.table_name AS foreign_table_name,
-			ccu.column_name AS foreign_column_name 
-		FROM information_schema.table_constraints AS tc 
-		JOIN information_schema.key_column_usage AS kcu
-			ON tc.constraint_name = kcu.constraint_name
-			AND tc.table_schema = kcu.table_schema
-		JOIN information_schema.constraint_column_usage AS ccu
-			ON ccu.constraint_name = tc.constraint_name
-		WHERE tc.constraint_type = 'FOREIGN KEY'
-		ORDER BY table_name`,
+			ns.nspname AS table_schema,
+			con.conname AS constraint_name,
+			cl.relname AS table_name,
+			att.attname AS column_name,
+			fns.nspname AS foreign_table_schema,
+			fcl.relname AS foreign_table_name,
+			fatt.attname AS foreign_column_name
+		FROM pg_constraint AS con
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, foreign_attnum, position)
+		JOIN pg_class AS cl ON cl.oid = con.conrelid
+		JOIN pg_namespace AS ns ON ns.oid = cl.relnamespace
+		JOIN pg_attribute AS att ON att.attrelid = con.conrelid AND att.attnum = k.attnum
+		JOIN pg_class AS fcl ON fcl.oid = con.confrelid
+		JOIN pg_namespace AS fns ON fns.oid = fcl.relnamespace
+		JOIN pg_attribute AS fatt ON fatt.attrelid = con.confrelid AND fatt.attnum = k.foreign_attnum
+		WHERE con.contype = 'f'
+		ORDER BY table_name, constraint_name, k.position`,
 	)
 
 	if err != nil {
@@ -147,16 +344,15 @@ func (pd *PostgresqlDriver) InsertStatement(t *Table) string {
 	var output strings.Builder
 
 	output.WriteString("INSERT INTO ")
-	output.WriteString(t.Name)
+	output.WriteString(quotePsqlTableName(t.Schema, t.Name))
 	output.WriteString(" (")
 
 	// Write out the column names
 	{
 		written := 0
 		for i, col := range t.Columns {
-			// Skip identity columns since we've already
-			// generated the data without this column
-			if slices.Contains(t.Metadata.IdentityColumns, i) {
+			// Omitted columns are left for the database to fill in
+			if slices.Contains(t.Metadata.OmittedColumns, i) {
 				continue
 			}
 
@@ -164,12 +360,23 @@ func (pd *PostgresqlDriver) InsertStatement(t *Table) string {
 				output.WriteRune(',')
 			}
 
-			output.WriteString(col.Name)
+			output.WriteString(quotePsqlIdentifier(col.Name))
 			written += 1
 		}
 	}
 
-	output.WriteString(") VALUES ")
+	output.WriteString(")")
+
+	// Explicit values for GENERATED ALWAYS identity columns are rejected
+	// unless we override them
+	for _, col := range t.Columns {
+		if _, ok := t.Metadata.IdentityValues[col.Name]; ok && col.IdentityGeneration.String == "ALWAYS" {
+			output.WriteString(" OVERRIDING SYSTEM VALUE")
+			break
+		}
+	}
+
+	output.WriteString(" VALUES ")
 
 	// Build the main part of the insert statement from the generated data
 	for i := range len(t.InsertRows) {
@@ -181,12 +388,16 @@ func (pd *PostgresqlDriver) InsertStatement(t *Table) string {
 		{
 			output.WriteRune('(')
 			written := 0
-			for _, row := range t.InsertRows[i] {
+			for j, row := range t.InsertRows[i] {
+				if slices.Contains(t.Metadata.OmittedColumns, j) {
+					continue
+				}
+
 				if written > 0 {
 					output.WriteRune(',')
 				}
 
-				output.WriteString(row)
+				output.WriteString(psqlLiteral(row))
 				written += 1
 			}
 
@@ -197,3 +408,160 @@ func (pd *PostgresqlDriver) InsertStatement(t *Table) string {
 	output.WriteRune(';')
 	return output.String()
 }
+
+// CopyStatement writes the generated rows as a COPY block that psql loads
+// far faster than an INSERT. COPY has no way of writing DEFAULT, so columns
+// left to their default are left out of the block altogether.
+func (pd *PostgresqlDriver) CopyStatement(t *Table) (string, error) {
+	columns, err := output.Columns(t)
+	if err != nil {
+		return "", errors.New(err.Error() + " and cannot be written with COPY")
+	}
+
+	var statement strings.Builder
+
+	statement.WriteString("COPY ")
+	statement.WriteString(quotePsqlTableName(t.Schema, t.Name))
+	statement.WriteString(" (")
+	for i, j := range columns {
+		if i > 0 {
+			statement.WriteRune(',')
+		}
+
+		statement.WriteString(quotePsqlIdentifier(t.Columns[j].Name))
+	}
+	statement.WriteString(") FROM stdin;\n")
+
+	// Escape the characters that would otherwise end a field or a row
+	escaper := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+	for _, row := range t.InsertRows {
+		for i, j := range columns {
+			if i > 0 {
+				statement.WriteRune('\t')
+			}
+
+			if row[j] == Null {
+				statement.WriteString(`\N`)
+				continue
+			}
+
+			statement.WriteString(escaper.Replace(Text(row[j])))
+		}
+
+		statement.WriteRune('\n')
+	}
+
+	statement.WriteString(`\.`)
+	return statement.String(), nil
+}
+
+// SequenceStatements moves the sequences of identity and serial columns past
+// the values written to them explicitly so later inserts don't collide.
+func (pd *PostgresqlDriver) SequenceStatements(t *Table) []string {
+	var statements []string
+
+	if t.Metadata.CreatedRows == 0 {
+		return statements
+	}
+
+	for _, i := range t.Metadata.IdentityColumns {
+		col := t.Columns[i]
+		next, ok := t.Metadata.IdentityValues[col.Name]
+		if !ok {
+			continue
+		}
+
+		increment := int64(1)
+		if col.IdentityIncrement.Valid {
+			increment = int64(col.IdentityIncrement.Int32)
+		}
+
+		tableName := strings.ReplaceAll(quotePsqlTableName(t.Schema, t.Name), "'", "''")
+		columnName := strings.ReplaceAll(col.Name, "'", "''")
+		statements = append(statements, fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), %d);", tableName, columnName, next-increment))
+	}
+
+	return statements
+}
+
+// psqlLiteral writes a value in the syntax Postgres reads it in
+func psqlLiteral(value Value) string {
+	switch value := value.(type) {
+	case Marker:
+		return string(value)
+	case bool, int64, Number:
+		return Text(value)
+	case Bits:
+		return "B'" + string(value) + "'"
+	case Array:
+		var array strings.Builder
+		array.WriteString("ARRAY")
+		psqlArrayElements(&array, value)
+		if value.ElementType != "" {
+			array.WriteString("::" + value.ElementType + strings.Repeat("[]", value.Dimensions))
+		}
+
+		return array.String()
+	default:
+		return "'" + strings.ReplaceAll(Text(value), "'", "''") + "'"
+	}
+}
+
+// psqlArrayElements writes out the elements of an array in brackets, nesting
+// the brackets of each sub-array
+func psqlArrayElements(array *strings.Builder, value Array) {
+	array.WriteRune('[')
+	for i, element := range value.Elements {
+		if i > 0 {
+			array.WriteRune(',')
+		}
+
+		if sub, ok := element.(Array); ok {
+			psqlArrayElements(array, sub)
+			continue
+		}
+
+		array.WriteString(psqlLiteral(element))
+	}
+	array.WriteRune(']')
+}
+
+// psqlPlainIdentifier matches the identifiers Postgres reads as they are
+// without quotes
+var psqlPlainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+
+// quotePsqlIdentifier quotes an identifier only when Postgres would need it
+// to be quoted, the same as the quote_ident function.
+func quotePsqlIdentifier(name string) string {
+	if !psqlPlainIdentifier.MatchString(name) || slices.Contains(psqlReservedKeywords, name) {
+		return pq.QuoteIdentifier(name)
+	}
+
+	return name
+}
+
+func quotePsqlTableName(schemaName, tableName string) string {
+	if schemaName == "" {
+		return quotePsqlIdentifier(tableName)
+	}
+
+	return quotePsqlIdentifier(schemaName) + "." + quotePsqlIdentifier(tableName)
+}
+
+// psqlReservedKeywords are the keywords that can't be used as a table or
+// column name without quoting it.
+var psqlReservedKeywords = []string{
+	"all", "analyse", "analyze", "and", "any", "array", "as", "asc", "asymmetric",
+	"authorization", "binary", "both", "case", "cast", "check", "collate", "collation",
+	"column", "concurrently", "constraint", "create", "cross", "current_catalog",
+	"current_date", "current_role", "current_schema", "current_time", "current_timestamp",
+	"current_user", "default", "deferrable", "desc", "distinct", "do", "else", "end",
+	"except", "false", "fetch", "for", "foreign", "freeze", "from", "full", "grant",
+	"group", "having", "ilike", "in", "initially", "inner", "intersect", "into", "is",
+	"isnull", "join", "lateral", "leading", "left", "like", "limit", "localtime",
+	"localtimestamp", "natural", "not", "notnull", "null", "offset", "on", "only", "or",
+	"order", "outer", "overlaps", "placing", "primary", "references", "returning",
+	"right", "select", "session_user", "similar", "some", "symmetric", "system_user",
+	"table", "tablesample", "then", "to", "trailing", "true", "union", "unique", "user",
+	"using", "variadic", "verbose", "when", "where", "window", "with",
+}
@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	. "dummy/sqldatabase/column"
 	. "dummy/sqldatabase/foreignkeyrelation"
 	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/value"
 )
 
 func createFakeColumn(name string, ordinalPosition int, isNullable bool, udtName string, isIdentity bool) *Column {
@@ -57,13 +59,114 @@ func TestToPsqlStatement(t *testing.T) {
 	table := createFakeTable("fake_table")
 	table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
 
-	table.InsertRows = append(table.InsertRows, []string{"DEFAULT", "Bill Bob", "2025-04-12 10:00:00 UTC"})
-	table.InsertRows = append(table.InsertRows, []string{"DEFAULT", "Jim George", "2025-04-12 10:00:00 UTC"})
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Bill Bob", "2025-04-12 10:00:00 UTC"})
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Jim O'George", "2025-04-12 10:00:00 UTC"})
 
 	actual := driver.InsertStatement(table)
-	expected := "INSERT INTO fake_table (id,name,created_at) VALUES (DEFAULT,Bill Bob,2025-04-12 10:00:00 UTC),(DEFAULT,Jim George,2025-04-12 10:00:00 UTC);"
+	expected := "INSERT INTO fake_table (id,name,created_at) VALUES (DEFAULT,'Bill Bob','2025-04-12 10:00:00 UTC'),(DEFAULT,'Jim O''George','2025-04-12 10:00:00 UTC');"
 
 	if strings.Compare(actual, expected) != 0 {
 		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
 	}
 }
+
+func TestToPsqlStatementQuoted(t *testing.T) {
+	driver := PostgresqlDriver{database: nil}
+
+	var tblCmds commands.TableCommands
+	table := NewTable("testing.Order")
+	table.Columns = append(table.Columns, *createFakeColumn("user", 1, false, "text", false))
+	table.Columns = append(table.Columns, *createFakeColumn("shipped_at", 2, true, "timestamp without time zone", false))
+	table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
+
+	table.InsertRows = append(table.InsertRows, []Value{"Bill Bob", Null})
+
+	actual := driver.InsertStatement(table)
+	expected := `INSERT INTO testing."Order" ("user",shipped_at) VALUES ('Bill Bob',NULL);`
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestToPsqlStatementDefaults(t *testing.T) {
+	driver := PostgresqlDriver{database: nil}
+
+	tblCmds := commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"created_at": {Default: DefaultOmit}},
+	}
+	table := createFakeTable("fake_table")
+	table.Metadata.DefaultPolicy = DefaultUse
+	table.Columns[1].ColumnDefault = sql.NullString{String: "'Nobody'::text", Valid: true}
+	table.Columns[2].ColumnDefault = sql.NullString{String: "now()", Valid: true}
+	err := table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.CreateData(2)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	actual := driver.InsertStatement(table)
+	expected := "INSERT INTO fake_table (id,name) VALUES (DEFAULT,DEFAULT),(DEFAULT,DEFAULT);"
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestPsqlSequenceStatements(t *testing.T) {
+	driver := PostgresqlDriver{database: nil}
+
+	var tblCmds commands.TableCommands
+	table := createFakeTable("testing.Order")
+	table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
+
+	if len(driver.SequenceStatements(table)) != 0 {
+		t.Errorf("Expected no statements without any rows")
+	}
+
+	table.Metadata.IdentityValues["id"] = 3
+	table.Metadata.CreatedRows = 2
+
+	actual := strings.Join(driver.SequenceStatements(table), "\n")
+	expected := `SELECT setval(pg_get_serial_sequence('testing."Order"', 'id'), 2);`
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestToPsqlCopyStatement(t *testing.T) {
+	driver := PostgresqlDriver{database: nil}
+
+	var tblCmds commands.TableCommands
+	table := createFakeTable("testing.Order")
+	table.Columns = append(table.Columns, *createFakeColumn("tags", 4, true, "_text", false))
+	table.Validate(tblCmds, make([]ForeignKeyRelation, 0))
+
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Bill 'Bob'\tBrown", "2025-04-12 10:00:00", Array{ElementType: "text", Dimensions: 1, Elements: []Value{`a\b`, `say "hi"`, Null}}})
+	table.InsertRows = append(table.InsertRows, []Value{Default, "Jim\nGeorge", Null, Array{ElementType: "text", Dimensions: 1, Elements: []Value{}}})
+
+	actual, err := driver.CopyStatement(table)
+	if err != nil {
+		t.Fatalf("Error calling CopyStatement: %s", err)
+	}
+
+	expected := `COPY testing."Order" (name,created_at,tags) FROM stdin;
+Bill 'Bob'\tBrown	2025-04-12 10:00:00	{"a\\\\b","say \\"hi\\"",NULL}
+Jim\nGeorge	\N	{}
+\.`
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	table.InsertRows[1][0] = int64(3)
+	_, err = driver.CopyStatement(table)
+	if err == nil {
+		t.Errorf("Expected an error mixing DEFAULT with other values")
+	}
+}
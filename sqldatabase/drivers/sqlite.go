@@ -0,0 +1,517 @@
+package drivers
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
+)
+
+type SqliteDriver struct {
+	database *sql.DB
+}
+
+func NewSqliteDriver(path string) (*SqliteDriver, error) {
+	// SQLite only checks FK constraints when asked to
+	conn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)", path)
+	db, err := sql.Open("sqlite", conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SqliteDriver{database: db}, nil
+}
+
+func (sd *SqliteDriver) Database() *sql.DB {
+	return sd.database
+}
+
+func (sd *SqliteDriver) TableColumns(schemaName, tableName string) ([]Column, error) {
+	var columns []Column
+
+	rows, err := sd.Database().Query(`SELECT cid, name, type, "notnull", dflt_value, pk FROM pragma_table_info(?, ?)`, tableName, sqliteSchema(schemaName))
+	if err != nil {
+		return make([]Column, 0), err
+	}
+
+	defer rows.Close()
+
+	primaryKeys := 0
+	var primaryKey []bool
+	for rows.Next() {
+		var c Column
+		var notNull, pk int
+
+		err := rows.Scan(&c.OrdinalPosition, &c.Name, &c.UdtName, &notNull, &c.ColumnDefault, &pk)
+		if err != nil {
+			return make([]Column, 0), err
+		}
+
+		c.OrdinalPosition += 1
+		c.UdtName = strings.ToLower(c.UdtName)
+		c.DataType = sqliteToDatatype(c.UdtName)
+		c.IsSelfReferencing = "NO"
+		c.IsIdentity = "NO"
+		c.IsUpdateable = "YES"
+
+		if notNull == 1 {
+			c.IsNullable = "NO"
+		} else {
+			c.IsNullable = "YES"
+		}
+
+		// Pick up the length, precision and scale from types like varchar(40)
+		// or decimal(10,2) even though SQLite itself ignores them
+		if start := strings.IndexRune(c.UdtName, '('); start >= 0 && strings.HasSuffix(c.UdtName, ")") {
+			args := strings.Split(c.UdtName[start+1:len(c.UdtName)-1], ",")
+			first, err := strconv.Atoi(strings.TrimSpace(args[0]))
+			if err == nil {
+				if c.DataType == "text" {
+					c.CharacterMaximumLength = sql.NullInt32{Int32: int32(first), Valid: true}
+				} else {
+					c.NumericPrecision = sql.NullInt32{Int32: int32(first), Valid: true}
+				}
+			}
+
+			if len(args) > 1 {
+				second, err := strconv.Atoi(strings.TrimSpace(args[1]))
+				if err == nil {
+					c.NumericScale = sql.NullInt32{Int32: int32(second), Valid: true}
+				}
+			}
+		}
+
+		if pk > 0 {
+			primaryKeys += 1
+		}
+
+		primaryKey = append(primaryKey, pk > 0)
+		columns = append(columns, c)
+	}
+
+	// A lone INTEGER PRIMARY KEY column is an alias for the rowid which
+	// SQLite fills in for us
+	if primaryKeys == 1 {
+		for i := range columns {
+			if primaryKey[i] && columns[i].UdtName == "integer" {
+				columns[i].IsIdentity = "YES"
+				columns[i].IdentityGeneration = sql.NullString{String: "BY DEFAULT", Valid: true}
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// ColumnValues returns the distinct non-null values stored in the given
+// columns of a table, ordered from largest to smallest.
+func (sd *SqliteDriver) ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error) {
+	var values [][]string
+
+	var columns, conditions, ordering []string
+	for _, name := range columnNames {
+		columns = append(columns, quoteSqliteIdentifier(name))
+		conditions = append(conditions, quoteSqliteIdentifier(name)+" IS NOT NULL")
+		ordering = append(ordering, quoteSqliteIdentifier(name)+" DESC")
+	}
+
+	rows, err := sd.Database().Query(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s ORDER BY %s LIMIT %d",
+		strings.Join(columns, ","),
+		quoteSqliteTableName(schemaName, tableName),
+		strings.Join(conditions, " AND "),
+		strings.Join(ordering, ","),
+		maxColumnValues,
+	))
+
+	if err != nil {
+		return make([][]string, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make([]string, len(columnNames))
+		dest := make([]any, len(columnNames))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return make([][]string, 0), err
+		}
+
+		values = append(values, row)
+	}
+
+	return values, nil
+}
+
+// UniqueConstraints returns the primary key, unique constraints and unique
+// indexes of a table. Partial indexes and indexes on expressions are left out,
+// as is an INTEGER PRIMARY KEY since SQLite fills it in for us.
+func (sd *SqliteDriver) UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error) {
+	var constraints []UniqueConstraint
+
+	rows, err := sd.Database().Query(`SELECT name, origin FROM pragma_index_list(?, ?) WHERE "unique" = 1 AND partial = 0 ORDER BY name`, tableName, sqliteSchema(schemaName))
+	if err != nil {
+		return make([]UniqueConstraint, 0), err
+	}
+
+	for rows.Next() {
+		var name, origin string
+		err := rows.Scan(&name, &origin)
+		if err != nil {
+			rows.Close()
+			return make([]UniqueConstraint, 0), err
+		}
+
+		constraints = append(constraints, UniqueConstraint{
+			TableSchema:    sqliteSchema(schemaName),
+			TableName:      tableName,
+			ConstraintName: name,
+			IsPrimaryKey:   origin == "pk",
+		})
+	}
+	rows.Close()
+
+	var indexed []UniqueConstraint
+	for _, constraint := range constraints {
+		rows, err := sd.Database().Query(`SELECT name FROM pragma_index_info(?, ?) ORDER BY seqno`, constraint.ConstraintName, sqliteSchema(schemaName))
+		if err != nil {
+			return make([]UniqueConstraint, 0), err
+		}
+
+		expression := false
+		for rows.Next() {
+			var column sql.NullString
+			err := rows.Scan(&column)
+			if err != nil {
+				rows.Close()
+				return make([]UniqueConstraint, 0), err
+			}
+
+			if !column.Valid {
+				expression = true
+			}
+
+			constraint.ColumnNames = append(constraint.ColumnNames, column.String)
+		}
+		rows.Close()
+
+		if !expression {
+			indexed = append(indexed, constraint)
+		}
+	}
+
+	return indexed, nil
+}
+
+// CheckConstraints returns no constraints since SQLite only keeps them in
+// the text of the CREATE TABLE statement.
+func (sd *SqliteDriver) CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error) {
+	return make([]CheckConstraint, 0), nil
+}
+
+func (sd *SqliteDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelation, error) {
+	fkMapping := make(map[string][]ForeignKeyRelation)
+
+	var schemaNames []string
+	{
+		rows, err := sd.Database().Query(`SELECT name FROM pragma_database_list ORDER BY seq`)
+		if err != nil {
+			return make(map[string][]ForeignKeyRelation), err
+		}
+
+		for rows.Next() {
+			var name string
+			err := rows.Scan(&name)
+			if err != nil {
+				rows.Close()
+				return make(map[string][]ForeignKeyRelation), err
+			}
+
+			schemaNames = append(schemaNames, name)
+		}
+		rows.Close()
+	}
+
+	// Tables can only reference tables in the same attached database
+	for _, schemaName := range schemaNames {
+		var tableNames []string
+		rows, err := sd.Database().Query(`SELECT name FROM ` + quoteSqliteIdentifier(schemaName) + `.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+		if err != nil {
+			return make(map[string][]ForeignKeyRelation), err
+		}
+
+		for rows.Next() {
+			var name string
+			err := rows.Scan(&name)
+			if err != nil {
+				rows.Close()
+				return make(map[string][]ForeignKeyRelation), err
+			}
+
+			tableNames = append(tableNames, name)
+		}
+		rows.Close()
+
+		for _, tableName := range tableNames {
+			fks, err := sd.tableForeignKeys(schemaName, tableName)
+			if err != nil {
+				return make(map[string][]ForeignKeyRelation), err
+			}
+
+			if len(fks) > 0 {
+				fkMapping[fks[0].QualifiedTableName()] = fks
+			}
+		}
+	}
+
+	return fkMapping, nil
+}
+
+func (sd *SqliteDriver) tableForeignKeys(schemaName, tableName string) ([]ForeignKeyRelation, error) {
+	var fks []ForeignKeyRelation
+
+	rows, err := sd.Database().Query(`SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?, ?) ORDER BY id, seq`, tableName, schemaName)
+	if err != nil {
+		return make([]ForeignKeyRelation, 0), err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var to sql.NullString
+		fk := ForeignKeyRelation{
+			TableSchema:        schemaName,
+			TableName:          tableName,
+			ForeignTableSchema: schemaName,
+		}
+
+		err := rows.Scan(&id, &fk.ForeignTableName, &fk.ColumnName, &to)
+		if err != nil {
+			return make([]ForeignKeyRelation, 0), err
+		}
+
+		// SQLite doesn't name FK constraints so make up a name for them
+		fk.ConstraintName = fmt.Sprintf("%s_fk%d", tableName, id)
+		fk.ForeignColumnName = to.String
+		fks = append(fks, fk)
+	}
+
+	// Without a column list the constraint references the primary key
+	for i := range fks {
+		if fks[i].ForeignColumnName != "" {
+			continue
+		}
+
+		var seq int
+		for _, fk := range fks[:i] {
+			if fk.ConstraintName == fks[i].ConstraintName {
+				seq += 1
+			}
+		}
+
+		err := sd.Database().QueryRow(`SELECT name FROM pragma_table_info(?, ?) WHERE pk = ?`, fks[i].ForeignTableName, schemaName, seq+1).Scan(&fks[i].ForeignColumnName)
+		if err != nil {
+			return make([]ForeignKeyRelation, 0), err
+		}
+	}
+
+	return fks, nil
+}
+
+func (sd *SqliteDriver) InsertStatement(t *Table) string {
+	return strings.Join(sd.InsertStatements(t), "\n")
+}
+
+// InsertStatements writes the INSERTs for the rows of a table. SQLite doesn't
+// understand DEFAULT in a VALUES list so we leave out the columns that should
+// use their default value instead, which takes a statement for each run of
+// rows that default the same columns.
+func (sd *SqliteDriver) InsertStatements(t *Table) []string {
+	if len(t.InsertRows) == 0 {
+		return sqliteInsertStatements(t, nil, sqliteDefaulted(t, nil))
+	}
+
+	var statements []string
+	start := 0
+	for end := 1; end <= len(t.InsertRows); end++ {
+		defaulted := sqliteDefaulted(t, t.InsertRows[start])
+		if end < len(t.InsertRows) && slices.Equal(defaulted, sqliteDefaulted(t, t.InsertRows[end])) {
+			continue
+		}
+
+		statements = append(statements, sqliteInsertStatements(t, t.InsertRows[start:end], defaulted)...)
+		start = end
+	}
+
+	return statements
+}
+
+// sqliteDefaulted returns which of the columns of a row are left out of the
+// INSERT so that they take their default value
+func sqliteDefaulted(t *Table, row []Value) []bool {
+	defaulted := make([]bool, len(t.Columns))
+	for i := range t.Columns {
+		defaulted[i] = slices.Contains(t.Metadata.OmittedColumns, i) || (row != nil && row[i] == Default)
+	}
+
+	return defaulted
+}
+
+// sqliteInsertStatements writes the INSERT for rows that leave out the same
+// columns. Rows without any columns left can only be inserted one at a time.
+func sqliteInsertStatements(t *Table, rows [][]Value, defaulted []bool) []string {
+	if len(rows) > 0 && !slices.Contains(defaulted, false) {
+		statements := make([]string, len(rows))
+		for i := range rows {
+			statements[i] = "INSERT INTO " + quoteSqliteTableName(t.Schema, t.Name) + " DEFAULT VALUES;"
+		}
+
+		return statements
+	}
+
+	var output strings.Builder
+
+	output.WriteString("INSERT INTO ")
+	output.WriteString(quoteSqliteTableName(t.Schema, t.Name))
+	output.WriteString(" (")
+
+	// Write out the column names
+	{
+		written := 0
+		for i, col := range t.Columns {
+			if defaulted[i] {
+				continue
+			}
+
+			if written > 0 {
+				output.WriteRune(',')
+			}
+
+			output.WriteString(quoteSqliteIdentifier(col.Name))
+			written += 1
+		}
+	}
+
+	output.WriteString(") VALUES ")
+
+	// Build the main part of the insert statement from the generated data
+	for i := range len(rows) {
+		if i > 0 {
+			output.WriteRune(',')
+		}
+
+		// Build the current row
+		{
+			output.WriteRune('(')
+			written := 0
+			for j, value := range rows[i] {
+				if defaulted[j] {
+					continue
+				}
+
+				if written > 0 {
+					output.WriteRune(',')
+				}
+
+				output.WriteString(sqliteLiteral(value))
+				written += 1
+			}
+
+			output.WriteRune(')')
+		}
+	}
+
+	output.WriteRune(';')
+	return []string{output.String()}
+}
+
+// SequenceStatements returns no statements since SQLite picks the next rowid
+// from the largest one in the table.
+func (sd *SqliteDriver) SequenceStatements(t *Table) []string {
+	return make([]string, 0)
+}
+
+// sqliteLiteral writes a value in the syntax SQLite reads it in
+func sqliteLiteral(value Value) string {
+	switch value := value.(type) {
+	case Marker:
+		return string(value)
+	case bool, int64, Number:
+		return Text(value)
+	case []byte:
+		return "X'" + hex.EncodeToString(value) + "'"
+	default:
+		return "'" + strings.ReplaceAll(Text(value), "'", "''") + "'"
+	}
+}
+
+func quoteSqliteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteSqliteTableName(schemaName, tableName string) string {
+	if schemaName == "" {
+		return quoteSqliteIdentifier(tableName)
+	}
+
+	return quoteSqliteIdentifier(schemaName) + "." + quoteSqliteIdentifier(tableName)
+}
+
+// sqliteSchema returns the name of the attached database to use, which is
+// the main database unless told otherwise
+func sqliteSchema(schemaName string) string {
+	if schemaName == "" {
+		return "main"
+	}
+
+	return schemaName
+}
+
+// sqliteToDatatype maps a declared column type onto the datatypes understood
+// by the generators. Most names are recognised the same way SQLite works out
+// a column's type affinity, with a few common names picked out beforehand so
+// they get more fitting data.
+func sqliteToDatatype(declared string) string {
+	declared = strings.ToUpper(declared)
+
+	switch {
+	case strings.Contains(declared, "BOOL"):
+		return "boolean"
+	case strings.Contains(declared, "DATE"), strings.Contains(declared, "TIMESTAMP"):
+		return "timestamp without time zone"
+	case strings.Contains(declared, "UUID"):
+		return "uuid"
+	case strings.Contains(declared, "JSON"):
+		return "json"
+	case strings.Contains(declared, "BIGINT"):
+		return "bigint"
+	case strings.Contains(declared, "SMALLINT"):
+		return "smallint"
+	case strings.Contains(declared, "TINYINT"):
+		return "tinyint"
+	case strings.Contains(declared, "INT"):
+		return "integer"
+	case strings.Contains(declared, "CHAR"), strings.Contains(declared, "CLOB"), strings.Contains(declared, "TEXT"):
+		return "text"
+	case declared == "", strings.Contains(declared, "BLOB"):
+		return "text"
+	case strings.Contains(declared, "REAL"), strings.Contains(declared, "FLOA"), strings.Contains(declared, "DOUB"):
+		return "double precision"
+	default:
+		return "numeric"
+	}
+}
@@ -0,0 +1,216 @@
+package drivers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"dummy/commands"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/value"
+)
+
+func createFakeSqliteDriver(t *testing.T) *SqliteDriver {
+	driver, err := NewSqliteDriver(filepath.Join(t.TempDir(), "dummy.db"))
+	if err != nil {
+		t.Fatalf("Error calling NewSqliteDriver: %s", err)
+	}
+	t.Cleanup(func() { driver.Database().Close() })
+
+	_, err = driver.Database().Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(40) NOT NULL);
+		CREATE TABLE todos (
+			id INTEGER PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users,
+			task TEXT NOT NULL,
+			complete BOOLEAN NOT NULL DEFAULT false,
+			price DECIMAL(10,2),
+			UNIQUE (user_id, task)
+		);
+		CREATE UNIQUE INDEX users_name_idx ON users (name);
+		CREATE UNIQUE INDEX users_lower_name_idx ON users (lower(name));
+		CREATE UNIQUE INDEX todos_open_task_idx ON todos (task) WHERE complete = false;`)
+	if err != nil {
+		t.Fatalf("Error creating the tables: %s", err)
+	}
+
+	return driver
+}
+
+func TestSqliteTableColumns(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	columns, err := driver.TableColumns("main", "todos")
+	if err != nil {
+		t.Fatalf("Error calling TableColumns: %s", err)
+	}
+
+	var actual []string
+	for _, col := range columns {
+		actual = append(actual, col.Name+":"+col.DataType+":"+col.IsNullable+":"+col.IsIdentity)
+	}
+
+	expected := "id:integer:YES:YES,user_id:integer:NO:NO,task:text:NO:NO,complete:boolean:NO:NO,price:numeric:YES:NO"
+	if strings.Join(actual, ",") != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, ","))
+	}
+
+	if columns[4].NumericPrecision.Int32 != 10 || columns[4].NumericScale.Int32 != 2 {
+		t.Errorf("Expected decimal(10,2) to have a precision of 10 and scale of 2")
+	}
+}
+
+func TestSqliteForeignKeyRelations(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	fks, err := driver.ForeignKeyRelations()
+	if err != nil {
+		t.Fatalf("Error calling ForeignKeyRelations: %s", err)
+	}
+
+	if len(fks["main.todos"]) != 1 {
+		t.Fatalf("Expected a single FK constraint on todos, got %d", len(fks["main.todos"]))
+	}
+
+	fk := fks["main.todos"][0]
+	if fk.ColumnName != "user_id" || fk.ForeignTableName != "users" || fk.ForeignColumnName != "id" {
+		t.Errorf("Expected todos.user_id to reference users.id, got %s.%s", fk.ForeignTableName, fk.ForeignColumnName)
+	}
+}
+
+func TestSqliteAttachedForeignKeyRelations(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	// Databases are attached to a single connection
+	driver.Database().SetMaxOpenConns(1)
+	_, err := driver.Database().Exec(`
+		ATTACH DATABASE '` + filepath.Join(t.TempDir(), "aux.db") + `' AS aux;
+		CREATE TABLE aux.teams (code TEXT PRIMARY KEY);
+		CREATE TABLE aux.members (team_code TEXT REFERENCES teams);`)
+	if err != nil {
+		t.Fatalf("Error creating the attached tables: %s", err)
+	}
+
+	fks, err := driver.ForeignKeyRelations()
+	if err != nil {
+		t.Fatalf("Error calling ForeignKeyRelations: %s", err)
+	}
+
+	if len(fks["aux.members"]) != 1 || len(fks["main.todos"]) != 1 {
+		t.Fatalf("Expected a FK constraint on both aux.members and main.todos, got %v", fks)
+	}
+
+	fk := fks["aux.members"][0]
+	if fk.QualifiedForeignTableName() != "aux.teams" || fk.ForeignColumnName != "code" {
+		t.Errorf("Expected aux.members.team_code to reference aux.teams.code, got %s.%s", fk.QualifiedForeignTableName(), fk.ForeignColumnName)
+	}
+}
+
+func TestSqliteUniqueConstraints(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	cases := map[string]string{
+		"users": "users_name_idx:name",
+		"todos": "sqlite_autoindex_todos_1:user_id,task",
+	}
+
+	for name, expected := range cases {
+		constraints, err := driver.UniqueConstraints("main", name)
+		if err != nil {
+			t.Fatalf("Error calling UniqueConstraints: %s", err)
+		}
+
+		var actual []string
+		for _, constraint := range constraints {
+			actual = append(actual, constraint.ConstraintName+":"+strings.Join(constraint.ColumnNames, ","))
+		}
+
+		if strings.Join(actual, " ") != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, " "))
+		}
+	}
+}
+
+func TestSqliteInsert(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	columns, err := driver.TableColumns("main", "users")
+	if err != nil {
+		t.Fatalf("Error calling TableColumns: %s", err)
+	}
+
+	var tblCmds commands.TableCommands
+	table := NewTable("users")
+	table.Columns = columns
+	table.Validate(tblCmds, nil)
+
+	err = table.CreateData(3)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	statement := driver.InsertStatement(table)
+	if !strings.HasPrefix(statement, `INSERT INTO "users" ("name") VALUES `) {
+		t.Errorf("Expected the rowid column to be left out, got:\n%s", statement)
+	}
+
+	_, err = driver.Database().Exec(statement)
+	if err != nil {
+		t.Fatalf("Error inserting the generated rows: %s", err)
+	}
+
+	values, err := driver.ColumnValues("main", "users", []string{"id"})
+	if err != nil {
+		t.Fatalf("Error calling ColumnValues: %s", err)
+	}
+
+	if len(values) != 3 || values[0][0] != "3" {
+		t.Errorf("Expected ids 3 to 1, got %v", values)
+	}
+}
+
+func TestSqliteInsertDefaults(t *testing.T) {
+	driver := createFakeSqliteDriver(t)
+
+	_, err := driver.Database().Exec(`CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT DEFAULT 'click')`)
+	if err != nil {
+		t.Fatalf("Error creating the table: %s", err)
+	}
+
+	columns, err := driver.TableColumns("main", "events")
+	if err != nil {
+		t.Fatalf("Error calling TableColumns: %s", err)
+	}
+
+	var tblCmds commands.TableCommands
+	table := NewTable("events")
+	table.Columns = columns
+	table.Validate(tblCmds, nil)
+
+	table.InsertRows = [][]Value{{Default, Default}, {Default, Default}, {Default, "view"}, {Default, "scroll"}, {Default, Default}}
+
+	actual := driver.InsertStatement(table)
+	expected := `INSERT INTO "events" DEFAULT VALUES;
+INSERT INTO "events" DEFAULT VALUES;
+INSERT INTO "events" ("kind") VALUES ('view'),('scroll');
+INSERT INTO "events" DEFAULT VALUES;`
+
+	if strings.Compare(actual, expected) != 0 {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	for _, statement := range driver.InsertStatements(table) {
+		_, err = driver.Database().Exec(statement)
+		if err != nil {
+			t.Fatalf("Error inserting the generated rows: %s", err)
+		}
+	}
+
+	var clicks int
+	err = driver.Database().QueryRow(`SELECT count(*) FROM events WHERE kind = 'click'`).Scan(&clicks)
+	if err != nil || clicks != 3 {
+		t.Errorf("Expected 3 rows to take the default kind, got %d", clicks)
+	}
+}
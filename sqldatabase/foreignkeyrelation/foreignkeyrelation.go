@@ -9,3 +9,23 @@ type ForeignKeyRelation struct {
 	ForeignTableName   string
 	ForeignColumnName  string
 }
+
+// QualifiedTableName returns the name of the referencing table along with
+// its schema.
+func (fk ForeignKeyRelation) QualifiedTableName() string {
+	return qualify(fk.TableSchema, fk.TableName)
+}
+
+// QualifiedForeignTableName returns the name of the referenced table along
+// with its schema.
+func (fk ForeignKeyRelation) QualifiedForeignTableName() string {
+	return qualify(fk.ForeignTableSchema, fk.ForeignTableName)
+}
+
+func qualify(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+
+	return schema + "." + name
+}
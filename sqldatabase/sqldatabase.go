@@ -1,10 +1,19 @@
 package sqldatabase
 
 import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"dummy/generate"
+	"dummy/sqldatabase/ddl"
 	"dummy/sqldatabase/drivers"
 
+	. "dummy/sqldatabase/column"
 	. "dummy/sqldatabase/foreignkeyrelation"
 	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/value"
 )
 
 // SqlDatabase maintains the driver which is a handle to the underlying
@@ -28,3 +37,329 @@ func New(driver drivers.SqlDatabaseDriver) (*SqlDatabase, error) {
 		Tables:      make([]Table, 0),
 	}, nil
 }
+
+// SortTables orders the tables so that every table comes after the tables
+// it references through its FK constraints. References to the table itself
+// or to tables that aren't being generated don't affect the order.
+func (db *SqlDatabase) SortTables(tables []*Table) ([]*Table, error) {
+	byName := make(map[string]*Table)
+	for _, t := range tables {
+		byName[t.QualifiedName()] = t
+	}
+
+	sorted := make([]*Table, 0, len(tables))
+	visited := make(map[string]bool)
+	var path []string
+
+	var visit func(t *Table) error
+	visit = func(t *Table) error {
+		if visited[t.QualifiedName()] {
+			return nil
+		}
+
+		for i, name := range path {
+			if name == t.QualifiedName() {
+				cycle := append(path[i:], t.QualifiedName())
+				return errors.New("Tables have circular FK constraints: " + strings.Join(cycle, " -> "))
+			}
+		}
+
+		path = append(path, t.QualifiedName())
+		for _, fk := range db.ForeignKeys[t.QualifiedName()] {
+			parent, ok := byName[fk.QualifiedForeignTableName()]
+			if !ok || parent == t {
+				continue
+			}
+
+			err := visit(parent)
+			if err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		visited[t.QualifiedName()] = true
+		sorted = append(sorted, t)
+		return nil
+	}
+
+	for _, t := range tables {
+		err := visit(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// ResolveIdentities gives explicit values to the identity and serial columns
+// that are referenced by the FK constraints of the tables being generated,
+// along with those whose policy asks for explicit values. The database would
+// otherwise pick these values at insert time and we'd have nothing to put in
+// the referencing columns.
+func (db *SqlDatabase) ResolveIdentities(tables []*Table) error {
+	for _, t := range tables {
+		for _, col := range t.Columns {
+			if !t.ExplicitIdentity(col) {
+				continue
+			}
+
+			err := db.resolveIdentity(t, col)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, child := range tables {
+		for _, fk := range child.Metadata.ForeignKeys {
+			parent := findTable(tables, fk.QualifiedForeignTableName())
+			if parent == nil {
+				continue
+			}
+
+			i := parent.ColumnIndex(fk.ForeignColumnName)
+			if i < 0 {
+				return errors.New("Column '" + fk.ForeignColumnName + "' referenced by FK constraint '" + fk.ConstraintName + "' does not exist on table " + parent.QualifiedName())
+			}
+
+			col := parent.Columns[i]
+			if !col.IsAutoIncrement() {
+				continue
+			}
+
+			err := db.resolveIdentity(parent, col)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveIdentity sets the first explicit value of an identity or serial
+// column, continuing on from the largest value already in the table
+func (db *SqlDatabase) resolveIdentity(t *Table, col Column) error {
+	if _, ok := t.Metadata.IdentityValues[col.Name]; ok {
+		return nil
+	}
+
+	next := int64(1)
+	if col.IdentityStart.Valid {
+		next = int64(col.IdentityStart.Int32)
+	}
+
+	existing, err := db.Driver.ColumnValues(t.Schema, t.Name, []string{col.Name})
+	if err != nil {
+		return err
+	}
+
+	if len(existing) > 0 {
+		largest, err := strconv.ParseInt(existing[0][0], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		next = largest + 1
+	}
+
+	t.Metadata.IdentityValues[col.Name] = next
+	return nil
+}
+
+// ResolveReferences has the tables being generated keep the values of the
+// columns referenced by the FK constraints of the other tables being
+// generated. Rows are dropped once they've been written, so these need to be
+// set up before any of the referenced tables are generated. Every row is
+// kept for the constraints that can't reference a row more than once.
+func (db *SqlDatabase) ResolveReferences(tables []*Table) {
+	for _, child := range tables {
+		for _, fk := range child.Metadata.ForeignKeys {
+			parent := findTable(tables, fk.QualifiedForeignTableName())
+			if parent == nil || parent == child {
+				continue
+			}
+
+			var columnNames []string
+			for _, constraintFk := range child.ForeignKeyColumns(fk.ConstraintName) {
+				columnNames = append(columnNames, constraintFk.ForeignColumnName)
+			}
+
+			parent.AddReference(columnNames, child.UniqueReference(fk.ConstraintName))
+		}
+	}
+}
+
+// ResolveForeignKeys collects the rows that the FK constraints of a table
+// can reference. These are the rows already generated for the referenced
+// tables along with the rows already stored in the database.
+func (db *SqlDatabase) ResolveForeignKeys(t *Table, tables []*Table) error {
+	for _, fk := range t.Metadata.ForeignKeys {
+		if _, ok := t.Metadata.ForeignKeyValues[fk.ConstraintName]; ok {
+			continue
+		}
+
+		constraintFks := t.ForeignKeyColumns(fk.ConstraintName)
+		var columnNames []string
+		for _, constraintFk := range constraintFks {
+			columnNames = append(columnNames, constraintFk.ForeignColumnName)
+		}
+
+		var values [][]Value
+
+		// Rows referencing their own table are added as they're generated
+		parent := findTable(tables, fk.QualifiedForeignTableName())
+		if parent != nil && parent != t {
+			values = append(values, parent.ReferenceValues(columnNames)...)
+		}
+
+		existing, err := db.Driver.ColumnValues(fk.ForeignTableSchema, fk.ForeignTableName, columnNames)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range existing {
+			reference := make([]Value, len(row))
+			for i, value := range row {
+				// Keep numbers and booleans as they are for the formats
+				// that tell them apart from text
+				col := t.Columns[t.ColumnIndex(constraintFks[i].ColumnName)]
+				reference[i], err = generate.FromText(col, value)
+				if err != nil {
+					return errors.New("Column '" + col.Name + "' references a row of " + fk.QualifiedForeignTableName() + " that it can't hold: " + err.Error())
+				}
+			}
+
+			values = append(values, reference)
+		}
+
+		t.Metadata.ForeignKeyValues[fk.ConstraintName] = values
+	}
+
+	return nil
+}
+
+// ResolveCheckConstraints limits the data generated for a table to what its
+// CHECK constraints allow. The constraints that can't be understood or
+// respected are returned rather than stopping generation since the rows may
+// still happen to satisfy them.
+func (db *SqlDatabase) ResolveCheckConstraints(t *Table) ([]error, error) {
+	constraints, err := db.Driver.CheckConstraints(t.Schema, t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignored []error
+	for _, constraint := range constraints {
+		conditions, err := ddl.ParseCheck(constraint.Definition)
+		if err == nil {
+			err = t.AddCheckConstraint(constraint, conditions)
+		}
+
+		if err != nil {
+			ignored = append(ignored, errors.New("Ignoring CHECK constraint '"+constraint.ConstraintName+"' on table "+t.QualifiedName()+": "+err.Error()))
+		}
+	}
+
+	return ignored, nil
+}
+
+// InsertStatements splits the rows generated for a table into INSERT
+// statements of at most the table's batch size, or DefaultBatchSize when it
+// has no batch size.
+func (db *SqlDatabase) InsertStatements(t *Table) []string {
+	size := t.Metadata.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	var statements []string
+	for start := 0; start < len(t.InsertRows); start += size {
+		batch := *t
+		batch.InsertRows = t.InsertRows[start:min(start+size, len(t.InsertRows))]
+		if driver, ok := db.Driver.(drivers.MultiInsertDriver); ok {
+			statements = append(statements, driver.InsertStatements(&batch)...)
+		} else {
+			statements = append(statements, db.Driver.InsertStatement(&batch))
+		}
+	}
+
+	return statements
+}
+
+// Inserter writes the rows generated for each table to the database as
+// they're generated, all within a single transaction
+type Inserter struct {
+	db *SqlDatabase
+	tx *sql.Tx
+}
+
+// NewInserter starts the transaction the generated rows are inserted in
+func (db *SqlDatabase) NewInserter() (*Inserter, error) {
+	database := db.Driver.Database()
+	if database == nil {
+		return nil, errors.New("Driver is not connected to a database")
+	}
+
+	tx, err := database.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Inserter{db: db, tx: tx}, nil
+}
+
+// Insert writes the rows currently held for a table to the database,
+// returning the number of rows inserted. The transaction is rolled back if
+// any of the inserts fail.
+func (in *Inserter) Insert(t *Table) (int64, error) {
+	var inserted int64
+	for _, statement := range in.db.InsertStatements(t) {
+		result, err := in.tx.Exec(statement)
+		if err != nil {
+			in.tx.Rollback()
+			return 0, errors.New("Could not insert into " + t.QualifiedName() + ": " + err.Error())
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			in.tx.Rollback()
+			return 0, err
+		}
+
+		inserted += rows
+	}
+
+	return inserted, nil
+}
+
+// UpdateSequences moves the sequences of a table past the explicit values
+// given to its identity columns once all of its rows have been inserted
+func (in *Inserter) UpdateSequences(t *Table) error {
+	for _, statement := range in.db.Driver.SequenceStatements(t) {
+		_, err := in.tx.Exec(statement)
+		if err != nil {
+			in.tx.Rollback()
+			return errors.New("Could not update the sequences of " + t.QualifiedName() + ": " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Commit keeps everything inserted
+func (in *Inserter) Commit() error {
+	return in.tx.Commit()
+}
+
+func findTable(tables []*Table, name string) *Table {
+	for _, t := range tables {
+		if t.QualifiedName() == name {
+			return t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package sqldatabase
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/table"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
+)
+
+// fakeDriver serves canned column values instead of querying a database
+type fakeDriver struct {
+	values map[string][][]string
+}
+
+func (fd *fakeDriver) Database() *sql.DB {
+	return nil
+}
+
+func (fd *fakeDriver) ForeignKeyRelations() (map[string][]ForeignKeyRelation, error) {
+	return make(map[string][]ForeignKeyRelation), nil
+}
+
+func (fd *fakeDriver) TableColumns(schemaName, tableName string) ([]Column, error) {
+	return make([]Column, 0), nil
+}
+
+func (fd *fakeDriver) ColumnValues(schemaName, tableName string, columnNames []string) ([][]string, error) {
+	return fd.values[tableName+"."+strings.Join(columnNames, ",")], nil
+}
+
+func (fd *fakeDriver) UniqueConstraints(schemaName, tableName string) ([]UniqueConstraint, error) {
+	return make([]UniqueConstraint, 0), nil
+}
+
+func (fd *fakeDriver) CheckConstraints(schemaName, tableName string) ([]CheckConstraint, error) {
+	return make([]CheckConstraint, 0), nil
+}
+
+func (fd *fakeDriver) InsertStatement(table *Table) string {
+	return fmt.Sprint(table.InsertRows)
+}
+
+func (fd *fakeDriver) SequenceStatements(table *Table) []string {
+	return make([]string, 0)
+}
+
+func fk(table, column, foreignTable, foreignColumn string) ForeignKeyRelation {
+	return ForeignKeyRelation{
+		ConstraintName:    table + "_" + column + "_fkey",
+		TableName:         table,
+		ColumnName:        column,
+		ForeignTableName:  foreignTable,
+		ForeignColumnName: foreignColumn,
+	}
+}
+
+func tableNames(tables []*Table) string {
+	var names []string
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestSortTables(t *testing.T) {
+	db := &SqlDatabase{
+		Driver: &fakeDriver{},
+		ForeignKeys: map[string][]ForeignKeyRelation{
+			"comments": {fk("comments", "post_id", "posts", "id"), fk("comments", "parent_id", "comments", "id")},
+			"posts":    {fk("posts", "user_id", "users", "id")},
+		},
+	}
+
+	tables := []*Table{NewTable("comments"), NewTable("posts"), NewTable("users")}
+	sorted, err := db.SortTables(tables)
+	if err != nil {
+		t.Fatalf("Error calling SortTables: %s", err)
+	}
+
+	expected := "users,posts,comments"
+	if actual := tableNames(sorted); actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestSortTablesCycle(t *testing.T) {
+	db := &SqlDatabase{
+		Driver: &fakeDriver{},
+		ForeignKeys: map[string][]ForeignKeyRelation{
+			"a": {fk("a", "b_id", "b", "id")},
+			"b": {fk("b", "a_id", "a", "id")},
+		},
+	}
+
+	_, err := db.SortTables([]*Table{NewTable("a"), NewTable("b")})
+	if err == nil {
+		t.Errorf("Expected an error for circular FK constraints")
+	}
+}
+
+func TestResolveForeignKeys(t *testing.T) {
+	fks := []ForeignKeyRelation{fk("posts", "user_id", "users", "id")}
+	db := &SqlDatabase{
+		Driver: &fakeDriver{
+			values: map[string][][]string{"users.id": {{"1"}}},
+		},
+		ForeignKeys: map[string][]ForeignKeyRelation{"posts": fks},
+	}
+
+	users := NewTable("users")
+	users.Columns = []Column{{Name: "id", IsIdentity: "YES", IsNullable: "NO"}}
+
+	posts := NewTable("posts")
+	posts.Columns = []Column{{Name: "user_id", IsIdentity: "NO", IsNullable: "NO"}}
+	posts.Metadata.ForeignKeys = fks
+
+	tables := []*Table{users, posts}
+	err := db.ResolveIdentities(tables)
+	if err != nil {
+		t.Fatalf("Error calling ResolveIdentities: %s", err)
+	}
+	db.ResolveReferences(tables)
+
+	// The users can still be referenced once their rows have been dropped
+	users.Metadata.BatchSize = 1
+	err = users.StreamData(2, func() error { return nil })
+	if err != nil {
+		t.Fatalf("Error calling StreamData: %s", err)
+	}
+
+	err = db.ResolveForeignKeys(posts, tables)
+	if err != nil {
+		t.Fatalf("Error calling ResolveForeignKeys: %s", err)
+	}
+
+	var actual []string
+	for _, row := range posts.Metadata.ForeignKeyValues["posts_user_id_fkey"] {
+		actual = append(actual, Text(row[0]))
+	}
+
+	// The new users continue on from the existing one
+	expected := "2,3,1"
+	if strings.Join(actual, ",") != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, strings.Join(actual, ","))
+	}
+}
+
+func TestResolveForeignKeysExisting(t *testing.T) {
+	fks := []ForeignKeyRelation{fk("votes", "user_id", "users", "id"), fk("votes", "up", "choices", "up")}
+	db := &SqlDatabase{
+		Driver: &fakeDriver{
+			values: map[string][][]string{"users.id": {{"7"}}, "choices.up": {{"t"}, {"false"}}},
+		},
+		ForeignKeys: map[string][]ForeignKeyRelation{"votes": fks},
+	}
+
+	votes := NewTable("votes")
+	votes.Columns = []Column{
+		{Name: "user_id", DataType: "integer", IsIdentity: "NO", IsNullable: "NO"},
+		{Name: "up", DataType: "boolean", IsIdentity: "NO", IsNullable: "NO"},
+	}
+	votes.Metadata.ForeignKeys = fks
+
+	err := db.ResolveForeignKeys(votes, []*Table{votes})
+	if err != nil {
+		t.Fatalf("Error calling ResolveForeignKeys: %s", err)
+	}
+
+	// The rows already in the database are read as the values generated
+	// for the columns would be
+	actual := fmt.Sprint(votes.Metadata.ForeignKeyValues["votes_user_id_fkey"], votes.Metadata.ForeignKeyValues["votes_up_fkey"])
+	expected := "[[7]] [[true] [false]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	if _, ok := votes.Metadata.ForeignKeyValues["votes_up_fkey"][0][0].(bool); !ok {
+		t.Errorf("Expected a boolean, got %T", votes.Metadata.ForeignKeyValues["votes_up_fkey"][0][0])
+	}
+}
+
+func TestInsertStatements(t *testing.T) {
+	db := &SqlDatabase{Driver: &fakeDriver{}}
+
+	table := NewTable("users")
+	table.InsertRows = [][]Value{{int64(1)}, {int64(2)}, {int64(3)}}
+
+	actual := strings.Join(db.InsertStatements(table), " ")
+	expected := "[[1] [2] [3]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	table.Metadata.BatchSize = 2
+	actual = strings.Join(db.InsertStatements(table), " ")
+	expected = "[[1] [2]] [[3]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
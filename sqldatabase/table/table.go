@@ -1,36 +1,202 @@
 package table
 
 import (
+	"cmp"
 	"errors"
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/brianvoe/gofakeit/v7"
+
 	"dummy/commands"
 	"dummy/generate"
 
+	. "dummy/sqldatabase/checkconstraint"
 	. "dummy/sqldatabase/column"
 	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
 )
 
+// maxRowAttempts limits how many times a row is generated again when its
+// values break a unique or CHECK constraint
+const maxRowAttempts = 1000
+
+// distinctAttempts is how many times a row is generated again when it
+// breaks a unique constraint before the values that collide are replaced
+// with ones counted out to be distinct
+const distinctAttempts = 10
+
 type Table struct {
+	Schema     string
 	Name       string
 	Metadata   Metadata
 	Columns    []Column
-	InsertRows [][]string
+	InsertRows [][]Value
 }
 
+// NewTable creates a table from its name, which may be qualified by
+// its schema.
 func NewTable(name string) *Table {
+	var schema string
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		schema, name = name[:i], name[i+1:]
+	}
+
 	return &Table{
-		Name: name,
+		Schema: schema,
+		Name:   name,
 		Metadata: Metadata{
-			CustomData: make(map[string]string),
+			ColumnOptions:    make(map[string]*generate.Options),
+			IdentityValues:   make(map[string]int64),
+			ForeignKeyValues: make(map[string][][]Value),
+			UniqueValues:     make(map[string]map[string]bool),
+			DistinctCounts:   make(map[string]int64),
+			CheckConditions:  make(map[string][]Condition),
 		},
 	}
 }
 
+// The ways of filling in a column that has a default value
+const (
+	DefaultGenerate = "generate" // generate a value like any other column
+	DefaultUse      = "default"  // write DEFAULT in place of a value
+	DefaultOmit     = "omit"     // leave the column out of the INSERT
+)
+
+// IsDefaultPolicy checks if the policy is one of the ways of filling in
+// a column with a default value. An empty policy generates values.
+func IsDefaultPolicy(policy string) bool {
+	return policy == "" || policy == DefaultGenerate || policy == DefaultUse || policy == DefaultOmit
+}
+
+// The ways of filling in identity and serial columns
+const (
+	IdentityDefault  = "default"  // let the database pick the values
+	IdentityExplicit = "explicit" // generate the values ourselves
+)
+
+// IsIdentityPolicy checks if the policy is one of the ways of filling in
+// identity and serial columns. An empty policy lets the database pick.
+func IsIdentityPolicy(policy string) bool {
+	return policy == "" || policy == IdentityDefault || policy == IdentityExplicit
+}
+
 type Metadata struct {
-	CustomData      map[string]string
+	ColumnOptions map[string]*generate.Options
+
+	// IdentityColumns holds the positions of the identity and serial
+	// columns, which the database fills in unless given explicit values
 	IdentityColumns []int
+
+	// IdentityPolicy is how identity and serial columns are filled in when
+	// they don't have a policy of their own
+	IdentityPolicy string
+
+	// OmittedColumns holds the positions of the columns left out of the
+	// INSERT so the database fills them in. Their values are DEFAULT.
+	OmittedColumns []int
+
+	// DefaultPolicy is how columns with a default value are filled in
+	// when they don't have a policy of their own
+	DefaultPolicy string
+
+	// NullRatio is the chance of a nullable column being NULL for columns
+	// without a ratio of their own
+	NullRatio float64
+
+	// BatchSize is the most rows generated and written at a time, which is
+	// also the most rows in a single INSERT, with 0 using DefaultBatchSize
+	BatchSize int
+
+	// CreatedRows is the number of rows generated for the table so far,
+	// including those no longer held in InsertRows
+	CreatedRows int
+
+	// IdentityValues holds the next value for identity columns that need
+	// explicit values, either because other tables reference them or
+	// because their policy asks for it
+	IdentityValues map[string]int64
+
+	ForeignKeys []ForeignKeyRelation
+
+	// ReferencedColumns holds the columns of the FK constraints on other
+	// tables that reference this table
+	ReferencedColumns [][]string
+
+	// ReferencedValues holds the values of each of ReferencedColumns, in the
+	// same order, for the rows generated that can be referenced. Unlike
+	// InsertRows, they're kept once the rows have been written, so past
+	// MaxReferencedValues only a sample of the rows is kept.
+	ReferencedValues [][][]Value
+
+	// ReferencedInFull has each of ReferencedColumns keep the values of
+	// every row rather than a sample, for the references that a unique
+	// constraint stops from being repeated
+	ReferencedInFull []bool
+
+	// ForeignKeyValues holds the rows that can be referenced by each of the
+	// table's foreign key constraints, keyed by the constraint name. The
+	// values in each row are ordered the same as the constraint's columns in
+	// ForeignKeys.
+	ForeignKeyValues map[string][][]Value
+
+	UniqueConstraints []UniqueConstraint
+
+	// UniqueValues holds the values generated so far for each of the
+	// table's unique constraints, keyed by the constraint name. They're
+	// needed to keep the values unique, so they grow with every row
+	// generated for the table even when streaming.
+	UniqueValues map[string]map[string]bool
+
+	// DistinctCounts holds how far each column has counted through the
+	// values made distinct for its unique constraints, keyed by the column
+	// name
+	DistinctCounts map[string]int64
+
+	CheckConstraints []CheckConstraint
+
+	// CheckConditions holds the conditions of each CHECK constraint that
+	// can only be checked once a row has been generated, keyed by the
+	// constraint name
+	CheckConditions map[string][]Condition
+}
+
+// QualifiedName returns the name of the table along with its schema.
+func (t *Table) QualifiedName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+
+	return t.Schema + "." + t.Name
+}
+
+// ColumnIndex returns the position of the named column in the table or -1
+// if the table has no such column.
+func (t *Table) ColumnIndex(name string) int {
+	for i, col := range t.Columns {
+		if col.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ForeignKeyColumns returns the foreign key relations that make up the
+// named constraint.
+func (t *Table) ForeignKeyColumns(constraintName string) []ForeignKeyRelation {
+	var fks []ForeignKeyRelation
+	for _, fk := range t.Metadata.ForeignKeys {
+		if fk.ConstraintName == constraintName {
+			fks = append(fks, fk)
+		}
+	}
+
+	return fks
 }
 
 func (t *Table) Validate(cmds commands.TableCommands, fks []ForeignKeyRelation) error {
@@ -38,44 +204,155 @@ func (t *Table) Validate(cmds commands.TableCommands, fks []ForeignKeyRelation)
 		return errors.New("Columns on table " + t.Name + " is empty")
 	}
 
+	if cmds.BatchSize < 0 {
+		return errors.New("Table " + t.QualifiedName() + " has a negative batch size")
+	} else if cmds.BatchSize > 0 {
+		t.Metadata.BatchSize = cmds.BatchSize
+	}
+
 	for _, col := range t.Columns {
 		name := col.Name
 		cmd, ok := cmds.Columns[name]
 		if ok {
 
-			// If it's a text column, ensure that the value requested is something supported
-			if col.UdtName == "text" && cmd != "" {
-				if !regexp.MustCompile(`(?i)(company|firstname|lastname|name|uuid)`).MatchString(cmd) {
-					return errors.New("Column '" + name + "' is not a text column and cannot generate a \"" + cmd + "\" for it.")
+			if cmd.Generator.Name != "" {
+				params, err := generate.ValidateGenerator(col, cmd.Generator.Name, cmd.Generator.Params)
+				if err != nil {
+					return err
+				}
+
+				t.columnOptions(name).Generator = strings.ToLower(cmd.Generator.Name)
+				t.columnOptions(name).GeneratorParams = params
+			}
+
+			if cmd.Template != "" {
+				if cmd.Generator.Name != "" {
+					return errors.New("Column '" + name + "' cannot have both a generator and a template")
+				}
+
+				var columnNames []string
+				for _, col := range t.Columns {
+					columnNames = append(columnNames, col.Name)
+				}
+
+				tmpl, err := generate.ParseTemplate(col, cmd.Template, columnNames)
+				if err != nil {
+					return err
+				}
+
+				t.columnOptions(name).Template = tmpl
+			}
+
+			if cmd.Regex != "" {
+				if cmd.Generator.Name != "" || cmd.Template != "" {
+					return errors.New("Column '" + name + "' can only have one of a generator, a template or a regex")
+				}
+
+				pattern, err := pattern(col, cmd.Regex)
+				if err != nil {
+					return err
+				}
+
+				t.columnOptions(name).Regex = pattern
+			}
+
+			if cmd.Min != "" || cmd.Max != "" || cmd.Distribution != nil {
+				err := t.rangeOptions(col, cmd)
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(cmd.Weights) > 0 {
+				err := validateWeights(col, cmd.Weights)
+				if err != nil {
+					return err
+				}
+
+				t.columnOptions(name).Weights = cmd.Weights
+			}
+
+			if cmd.Array != nil {
+				array, err := arrayOptions(col, *cmd.Array)
+				if err != nil {
+					return err
+				}
+
+				t.columnOptions(name).Array = array
+			}
+
+			if cmd.Default != "" {
+				if !IsDefaultPolicy(cmd.Default) {
+					return errors.New("Column '" + name + "' has an unknown default policy \"" + cmd.Default + "\"")
+				}
+
+				if !col.ColumnDefault.Valid {
+					return errors.New("Column '" + name + "' has no default value to use")
+				}
+
+				t.columnOptions(name).Default = cmd.Default
+			}
+
+			if cmd.Identity != "" {
+				if !IsIdentityPolicy(cmd.Identity) {
+					return errors.New("Column '" + name + "' has an unknown identity policy \"" + cmd.Identity + "\"")
+				}
+
+				if !col.IsAutoIncrement() {
+					return errors.New("Column '" + name + "' is not an identity or serial column")
+				}
+
+				t.columnOptions(name).Identity = cmd.Identity
+			}
+
+			if cmd.NullRatio != nil {
+				if col.IsNullable != "YES" {
+					return errors.New("Column '" + name + "' is not nullable and cannot have a null ratio")
 				}
 
-				t.Metadata.CustomData[name] = strings.ToLower(cmd)
+				if *cmd.NullRatio < 0 || *cmd.NullRatio > 1 {
+					return errors.New("Column '" + name + "' has a null ratio outside of 0 to 1")
+				}
+
+				t.columnOptions(name).NullRatio = cmd.NullRatio
 			}
 		}
 
 		// Check if the column we're working with has a FK constraint
 		for _, fk := range fks {
 			if fk.ColumnName == col.Name {
-				if col.IsNullable == "NO" {
-					return errors.New("Column '" + col.Name + "' has a FK constraint named '" + fk.ConstraintName + "' that is not nullable")
+				// A row can't reference itself before it exists
+				if fk.QualifiedForeignTableName() == t.QualifiedName() && col.IsNullable == "NO" {
+					return errors.New("Column '" + col.Name + "' has a self-referencing FK constraint named '" + fk.ConstraintName + "' that is not nullable")
 				}
 			}
 		}
 	}
 
+	t.Metadata.ForeignKeys = fks
+
+	t.Metadata.IdentityColumns = nil
+	t.Metadata.OmittedColumns = nil
+	for i, col := range t.Columns {
+		if col.IsAutoIncrement() {
+			t.Metadata.IdentityColumns = append(t.Metadata.IdentityColumns, i)
+		}
+
+		if t.defaultPolicy(col) == DefaultOmit {
+			t.Metadata.OmittedColumns = append(t.Metadata.OmittedColumns, i)
+		}
+	}
+
 	return nil
 }
 
 func (t *Table) GuessCustomTextFieldGenerators() {
-	customData := t.Metadata.CustomData
-
 	for _, col := range t.Columns {
-		_, exists := customData[col.Name]
-		if exists {
+		if options, ok := t.Metadata.ColumnOptions[col.Name]; ok && (options.Generator != "" || options.Template != nil || options.Regex != nil) {
 			continue
 		}
 
-		if col.DataType != "text" {
+		if !generate.IsText(col.DataType) {
 			continue
 		}
 
@@ -85,15 +362,15 @@ func (t *Table) GuessCustomTextFieldGenerators() {
 		case regexp.MustCompile(`(?i)^first[_-]*name$`).MatchString(colName):
 			fallthrough
 		case regexp.MustCompile(`(?i)^given[_-]*name$`).MatchString(colName):
-			customData[colName] = "firstname"
+			t.columnOptions(colName).Generator = "firstname"
 			continue
 		case regexp.MustCompile(`(?i)^last[_-]*name$`).MatchString(colName):
 			fallthrough
 		case regexp.MustCompile(`(?i)^family[_-]*name$`).MatchString(colName):
-			customData[colName] = "lastname"
+			t.columnOptions(colName).Generator = "lastname"
 			continue
 		case regexp.MustCompile(`(?i)^(company|firm|business|corporation|establishment|organization|institution)[-_]*(name)?$`).MatchString(colName):
-			customData[colName] = "company"
+			t.columnOptions(colName).Generator = "company"
 			continue
 		}
 
@@ -103,9 +380,9 @@ func (t *Table) GuessCustomTextFieldGenerators() {
 		case regexp.MustCompile(`(?i)users`).MatchString(tableName):
 			switch true {
 			case regexp.MustCompile(`(?i)^id$`).MatchString(colName):
-				customData[colName] = "uuid"
+				t.columnOptions(colName).Generator = "uuid"
 			case regexp.MustCompile(`(?i)^name$`).MatchString(colName):
-				customData[colName] = "name"
+				t.columnOptions(colName).Generator = "name"
 			default:
 				// No guesses to offer
 				continue
@@ -113,9 +390,9 @@ func (t *Table) GuessCustomTextFieldGenerators() {
 		case regexp.MustCompile(`(?i)(company|firm|business|corporation|establishment|organization|institution)`).MatchString(tableName):
 			switch true {
 			case regexp.MustCompile(`(?i)^id$`).MatchString(colName):
-				customData[colName] = "uuid"
+				t.columnOptions(colName).Generator = "uuid"
 			case regexp.MustCompile(`(?i)^name$`).MatchString(colName):
-				customData[colName] = "company"
+				t.columnOptions(colName).Generator = "company"
 			default:
 				// No guesses to offer
 				continue
@@ -124,27 +401,1100 @@ func (t *Table) GuessCustomTextFieldGenerators() {
 	}
 }
 
+// DefaultBatchSize is how many rows are generated and written at a time for
+// tables without a batch size of their own
+const DefaultBatchSize = 1000
+
+// StreamData generates count rows in batches, handing each batch to write in
+// InsertRows before generating the next, so only a single batch of rows is
+// held in memory no matter how many rows are generated. What's kept across
+// batches is the values of unique constraints, which grow with every row,
+// and the rows other tables reference, which are sampled once there are
+// more than MaxReferencedValues of them unless a unique constraint stops
+// them from being referenced more than once.
+func (t *Table) StreamData(count int, write func() error) error {
+	size := t.Metadata.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	for created := 0; created < count; created += size {
+		t.InsertRows = t.InsertRows[:0]
+
+		err := t.CreateData(min(size, count-created))
+		if err != nil {
+			return err
+		}
+
+		err = write()
+		if err != nil {
+			return err
+		}
+	}
+
+	t.InsertRows = nil
+	return nil
+}
+
 func (t *Table) CreateData(count int) error {
 	for range count {
-		var row []string
+		var row []Value
+		for attempt := 1; ; attempt++ {
+			identities := maps.Clone(t.Metadata.IdentityValues)
+			references := maps.Clone(t.Metadata.ForeignKeyValues)
+
+			var err error
+			row, err = t.createRow()
+			if err != nil {
+				return err
+			}
+
+			// The row is checked for uniqueness last since checking the
+			// CHECK constraints can swap values around
+			var broken string
+			if constraint, checked := t.checkRow(row); !checked {
+				broken = "CHECK constraint '" + constraint.ConstraintName + "'"
+			} else if constraint, unique := t.uniqueRow(row); !unique && (attempt < distinctAttempts || !t.distinguish(row)) {
+				broken = "unique constraint '" + constraint.ConstraintName + "' on columns " + strings.Join(constraint.ColumnNames, ", ")
+			} else {
+				break
+			}
+
+			// Hand out the same identity values and references again
+			t.Metadata.IdentityValues = identities
+			t.Metadata.ForeignKeyValues = references
+
+			if attempt == maxRowAttempts {
+				return errors.New("Could not generate values satisfying " + broken + " of table " + t.QualifiedName() + " after " + strconv.Itoa(attempt) + " attempts")
+			}
+		}
+
+		t.addUniqueValues(row)
+		t.InsertRows = append(t.InsertRows, row)
+		t.Metadata.CreatedRows++
+		t.addReferences(row)
+		t.addSelfReferences(row)
+	}
+
+	return nil
+}
+
+func (t *Table) createRow() ([]Value, error) {
+	var row []Value
+	var templated []int
+
+	// Pick the referenced row for each of the FK constraints up front so
+	// that composite keys all point to the same row
+	references := make(map[string][]Value)
+	for _, fk := range t.Metadata.ForeignKeys {
+		if _, picked := references[fk.ConstraintName]; picked {
+			continue
+		}
+
+		values := t.Metadata.ForeignKeyValues[fk.ConstraintName]
+		if len(values) == 0 {
+			continue
+		}
+
+		i := gofakeit.IntRange(0, len(values)-1)
+		references[fk.ConstraintName] = values[i]
+
+		// A row can only be referenced once when the FK is unique, so it's
+		// taken out of the ones left to pick from. Swapping it to the end
+		// keeps the same rows within the old slice for when the row is
+		// generated again.
+		if t.UniqueReference(fk.ConstraintName) {
+			last := len(values) - 1
+			values[i], values[last] = values[last], values[i]
+			t.Metadata.ForeignKeyValues[fk.ConstraintName] = values[:last]
+		}
+	}
 
-		for _, col := range t.Columns {
-			if col.IsIdentity == "YES" {
-				row = append(row, "DEFAULT")
+	for _, col := range t.Columns {
+		if col.IsAutoIncrement() {
+			next, ok := t.Metadata.IdentityValues[col.Name]
+			if !ok {
+				row = append(row, Default)
 				continue
 			}
 
-			var value string
-			value, err := generate.FakeData(col.DataType, col.UdtName, col.Name, &t.Metadata.CustomData)
+			increment := int64(1)
+			if col.IdentityIncrement.Valid {
+				increment = int64(col.IdentityIncrement.Int32)
+			}
+
+			row = append(row, next)
+			t.Metadata.IdentityValues[col.Name] = next + increment
+			continue
+		}
+
+		if policy := t.defaultPolicy(col); policy == DefaultUse || policy == DefaultOmit {
+			row = append(row, Default)
+			continue
+		}
+
+		if t.null(col) {
+			row = append(row, Null)
+			continue
+		}
+
+		if fk, ok := t.foreignKey(col.Name); ok {
+			value, err := t.referencedValue(col, fk, references)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			row = append(row, value)
+			continue
 		}
 
-		t.InsertRows = append(t.InsertRows, row)
+		// Templates can refer to any of the other columns so they're
+		// filled in once the rest of the row has been
+		if options, ok := t.Metadata.ColumnOptions[col.Name]; ok && options.Template != nil {
+			templated = append(templated, len(row))
+			row = append(row, Null)
+			continue
+		}
+
+		value, err := generate.FakeData(col, t.Metadata.ColumnOptions[col.Name])
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, value)
+	}
+
+	if len(templated) > 0 {
+		err := t.fillTemplates(row, templated)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
+
+// fillTemplates fills in the columns of a row made from templates, in the
+// order of the table's columns. NULL and DEFAULT values are given to the
+// templates as empty text.
+func (t *Table) fillTemplates(row []Value, templated []int) error {
+	values := make(map[string]string)
+	for i, col := range t.Columns {
+		values[col.Name] = ""
+		if row[i] != Null && row[i] != Default && !slices.Contains(templated, i) {
+			values[col.Name] = Text(row[i])
+		}
+	}
+
+	for _, i := range templated {
+		col := t.Columns[i]
+		value, err := generate.FromTemplate(col, t.Metadata.ColumnOptions[col.Name], values)
+		if err != nil {
+			return errors.New("Column '" + col.Name + "' of table " + t.QualifiedName() + ": " + err.Error())
+		}
+
+		row[i] = value
+		values[col.Name] = Text(value)
+	}
+
+	return nil
+}
+
+// defaultPolicy returns how a column is filled in, which is always by
+// generating a value when the column has no default
+func (t *Table) defaultPolicy(col Column) string {
+	if !col.ColumnDefault.Valid || col.IsAutoIncrement() {
+		return DefaultGenerate
+	}
+
+	policy := t.Metadata.DefaultPolicy
+	if options, ok := t.Metadata.ColumnOptions[col.Name]; ok && options.Default != "" {
+		policy = options.Default
+	}
+
+	if policy == "" {
+		return DefaultGenerate
+	}
+
+	return policy
+}
+
+// ExplicitIdentity checks if an identity or serial column is given values by
+// us rather than by the database
+func (t *Table) ExplicitIdentity(col Column) bool {
+	policy := t.Metadata.IdentityPolicy
+	if options, ok := t.Metadata.ColumnOptions[col.Name]; ok && options.Identity != "" {
+		policy = options.Identity
+	}
+
+	return col.IsAutoIncrement() && policy == IdentityExplicit
+}
+
+// null decides if a nullable column is left NULL in a row
+func (t *Table) null(col Column) bool {
+	if col.IsNullable != "YES" {
+		return false
+	}
+
+	ratio := t.Metadata.NullRatio
+	if options, ok := t.Metadata.ColumnOptions[col.Name]; ok && options.NullRatio != nil {
+		ratio = *options.NullRatio
+	}
+
+	return ratio > 0 && gofakeit.Float64() < ratio
+}
+
+// uniqueRow checks the row against the rows generated before it, returning
+// the first unique constraint it breaks. Values already stored in the
+// database aren't checked.
+func (t *Table) uniqueRow(row []Value) (UniqueConstraint, bool) {
+	for _, constraint := range t.Metadata.UniqueConstraints {
+		key, ok := t.uniqueKey(constraint, row)
+		if ok && t.Metadata.UniqueValues[constraint.ConstraintName][key] {
+			return constraint, false
+		}
+	}
+
+	return UniqueConstraint{}, true
+}
+
+// distinguish replaces a value of each unique constraint the row breaks
+// with one counted out for its column, so that small sets of values don't
+// run out of random ones that haven't been used. It's false when the row
+// still breaks one of the table's constraints.
+func (t *Table) distinguish(row []Value) bool {
+	for _, constraint := range t.Metadata.UniqueConstraints {
+		if key, ok := t.uniqueKey(constraint, row); ok && t.Metadata.UniqueValues[constraint.ConstraintName][key] {
+			if !t.distinguishKey(row, constraint) {
+				return false
+			}
+		}
+	}
+
+	if _, checked := t.checkRow(row); !checked {
+		return false
+	}
+
+	_, unique := t.uniqueRow(row)
+	return unique
+}
+
+// distinguishKey counts through the values of one of the constraint's
+// columns until the row's key hasn't been used. The values referenced by
+// foreign keys and those of identity columns are left as they are.
+func (t *Table) distinguishKey(row []Value, constraint UniqueConstraint) bool {
+	if t.Metadata.DistinctCounts == nil {
+		t.Metadata.DistinctCounts = make(map[string]int64)
+	}
+
+	for _, name := range constraint.ColumnNames {
+		i := t.ColumnIndex(name)
+		if i < 0 || t.Columns[i].IsAutoIncrement() {
+			continue
+		}
+
+		if _, ok := t.foreignKey(name); ok {
+			continue
+		}
+
+		original := row[i]
+		options := t.Metadata.ColumnOptions[name]
+		for {
+			value, ok := generate.Distinct(t.Columns[i], options, original, t.Metadata.DistinctCounts[name])
+			if !ok {
+				break
+			}
+
+			t.Metadata.DistinctCounts[name]++
+			row[i] = value
+			if key, _ := t.uniqueKey(constraint, row); t.fits(i, value) && !t.Metadata.UniqueValues[constraint.ConstraintName][key] {
+				return true
+			}
+		}
+
+		row[i] = original
+	}
+
+	return false
+}
+
+func (t *Table) addUniqueValues(row []Value) {
+	for _, constraint := range t.Metadata.UniqueConstraints {
+		key, ok := t.uniqueKey(constraint, row)
+		if !ok {
+			continue
+		}
+
+		values, ok := t.Metadata.UniqueValues[constraint.ConstraintName]
+		if !ok {
+			values = make(map[string]bool)
+			t.Metadata.UniqueValues[constraint.ConstraintName] = values
+		}
+
+		values[key] = true
+	}
+}
+
+// uniqueKey joins together the row's values for the columns of a unique
+// constraint. Rows with a NULL value never clash and rows using a column's
+// default are left to the database, so neither has a key.
+func (t *Table) uniqueKey(constraint UniqueConstraint, row []Value) (string, bool) {
+	var key strings.Builder
+	for i, name := range constraint.ColumnNames {
+		index := t.ColumnIndex(name)
+		if index < 0 || row[index] == Null || row[index] == Default {
+			return "", false
+		}
+
+		if i > 0 {
+			key.WriteByte(0)
+		}
+
+		key.WriteString(Text(row[index]))
+	}
+
+	return key.String(), true
+}
+
+// columnOptions returns the options for generating a column's data,
+// creating them if the column has none yet.
+func (t *Table) columnOptions(name string) *generate.Options {
+	options, ok := t.Metadata.ColumnOptions[name]
+	if !ok {
+		options = &generate.Options{}
+		t.Metadata.ColumnOptions[name] = options
+	}
+
+	return options
+}
+
+// validateWeights checks that every weighted value is one the column
+// can actually hold
+func validateWeights(col Column, weights map[string]float64) error {
+	if len(col.EnumValues) == 0 {
+		return errors.New("Column '" + col.Name + "' is not an enum column and cannot have weights")
+	}
+
+	total := 0.0
+	for value, weight := range weights {
+		if !slices.Contains(col.EnumValues, value) {
+			return errors.New("Column '" + col.Name + "' has a weight for \"" + value + "\" which is not one of its values: " + strings.Join(col.EnumValues, ", "))
+		}
+
+		if weight < 0 {
+			return errors.New("Column '" + col.Name + "' has a negative weight for \"" + value + "\"")
+		}
+
+		total += weight
+	}
+
+	// Values without a weight are given a weight of 1
+	if total == 0 && len(weights) == len(col.EnumValues) {
+		return errors.New("Column '" + col.Name + "' has a weight of 0 for all of its values")
 	}
 
 	return nil
 }
+
+// rangeOptions sets the bounds and distribution of a numeric, date or
+// timestamp column. Buckets bound a column that isn't already.
+func (t *Table) rangeOptions(col Column, cmd commands.ColumnCommands) error {
+	if !generate.IsNumeric(col.DataType) && !generate.IsTemporal(col.DataType) {
+		return errors.New("Column '" + col.Name + "' of type " + col.DataType + " can't be limited to a range of values")
+	}
+
+	options := t.columnOptions(col.Name)
+	if cmd.Min != "" {
+		n, err := generate.ParseBound(col, cmd.Min)
+		if err != nil {
+			return err
+		}
+
+		options.Min = &generate.Bound{Value: n}
+	}
+
+	if cmd.Max != "" {
+		n, err := generate.ParseBound(col, cmd.Max)
+		if err != nil {
+			return err
+		}
+
+		options.Max = &generate.Bound{Value: n}
+	}
+
+	if options.Min != nil && options.Max != nil && options.Min.Value > options.Max.Value {
+		return errors.New("Column '" + col.Name + "' has a min greater than its max")
+	}
+
+	if cmd.Distribution == nil {
+		return nil
+	}
+
+	distribution, err := distribution(col, *cmd.Distribution)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range distribution.Buckets {
+		if options.Min == nil || cmd.Min == "" && bucket.Min < options.Min.Value {
+			options.Min = &generate.Bound{Value: bucket.Min}
+		}
+
+		if options.Max == nil || cmd.Max == "" && bucket.Max > options.Max.Value {
+			options.Max = &generate.Bound{Value: bucket.Max}
+		}
+
+		if bucket.Min < options.Min.Value || bucket.Max > options.Max.Value {
+			return errors.New("Column '" + col.Name + "' has a bucket outside of its min and max")
+		}
+	}
+
+	options.Distribution = distribution
+	return nil
+}
+
+// distribution reads the distribution of a numeric, date or timestamp column
+func distribution(col Column, cmd commands.DistributionCommands) (*generate.Distribution, error) {
+	distribution := &generate.Distribution{Type: cmd.Type, Rate: cmd.Rate, S: cmd.S, V: cmd.V}
+	if distribution.Type == "" && len(cmd.Buckets) > 0 {
+		distribution.Type = generate.DistributionBuckets
+	}
+
+	if !generate.IsDistribution(distribution.Type) {
+		return nil, errors.New("Column '" + col.Name + "' has an unknown distribution \"" + cmd.Type + "\", expected one of: uniform, normal, exponential, zipf, buckets")
+	}
+
+	if cmd.Mean != "" {
+		n, err := generate.ParseBound(col, cmd.Mean)
+		if err != nil {
+			return nil, err
+		}
+
+		distribution.Mean = &n
+	}
+
+	if cmd.StdDev != "" {
+		n, err := generate.ParseSpan(col, cmd.StdDev)
+		if err != nil {
+			return nil, err
+		}
+
+		if n <= 0 {
+			return nil, errors.New("Column '" + col.Name + "' has a standard deviation that isn't above 0")
+		}
+
+		distribution.StdDev = n
+	}
+
+	if cmd.Rate < 0 {
+		return nil, errors.New("Column '" + col.Name + "' has a negative rate")
+	}
+
+	if cmd.S != 0 && cmd.S <= 1 {
+		return nil, errors.New("Column '" + col.Name + "' has a Zipf s that isn't above 1")
+	}
+
+	if cmd.V != 0 && cmd.V < 1 {
+		return nil, errors.New("Column '" + col.Name + "' has a Zipf v below 1")
+	}
+
+	switch cmd.Toward {
+	case "", "min":
+	case "max":
+		distribution.TowardMax = true
+	default:
+		return nil, errors.New("Column '" + col.Name + "' has a distribution toward \"" + cmd.Toward + "\", expected min or max")
+	}
+
+	if distribution.Type != generate.DistributionBuckets {
+		return distribution, nil
+	}
+
+	total := 0.0
+	for _, bucketCmd := range cmd.Buckets {
+		var bucket generate.Bucket
+		var err error
+		bucket.Min, err = generate.ParseBound(col, bucketCmd.Min)
+		if err != nil {
+			return nil, err
+		}
+
+		bucket.Max, err = generate.ParseBound(col, bucketCmd.Max)
+		if err != nil {
+			return nil, err
+		}
+
+		if bucket.Min > bucket.Max {
+			return nil, errors.New("Column '" + col.Name + "' has a bucket with a min greater than its max")
+		}
+
+		if bucketCmd.Weight < 0 {
+			return nil, errors.New("Column '" + col.Name + "' has a bucket with a negative weight")
+		}
+
+		bucket.Weight = bucketCmd.Weight
+		total += bucket.Weight
+		distribution.Buckets = append(distribution.Buckets, bucket)
+	}
+
+	if total == 0 {
+		return nil, errors.New("Column '" + col.Name + "' has no buckets with a weight above 0")
+	}
+
+	return distribution, nil
+}
+
+// arrayOptions checks the shape requested for an array column, filling in
+// the defaults for anything left out
+func arrayOptions(col Column, cmd commands.ArrayCommands) (*generate.ArrayOptions, error) {
+	if col.DataType != "ARRAY" {
+		return nil, errors.New("Column '" + col.Name + "' is not an array column and cannot have array options")
+	}
+
+	array := generate.DefaultArrayOptions
+	switch {
+	case cmd.MinLength != nil && cmd.MaxLength != nil:
+		array.MinLength, array.MaxLength = *cmd.MinLength, *cmd.MaxLength
+	case cmd.MinLength != nil:
+		array.MinLength = *cmd.MinLength
+		array.MaxLength = max(array.MaxLength, array.MinLength)
+	case cmd.MaxLength != nil:
+		array.MaxLength = *cmd.MaxLength
+		array.MinLength = min(array.MinLength, array.MaxLength)
+	}
+
+	if array.MinLength < 0 || array.MinLength > array.MaxLength {
+		return nil, errors.New("Column '" + col.Name + "' has an invalid array length of " + strconv.Itoa(array.MinLength) + " to " + strconv.Itoa(array.MaxLength))
+	}
+
+	if cmd.NullRatio < 0 || cmd.NullRatio > 1 {
+		return nil, errors.New("Column '" + col.Name + "' has an array null ratio outside of 0 to 1")
+	}
+
+	array.NullRatio = cmd.NullRatio
+	return &array, nil
+}
+
+// AddCheckConstraint limits the data generated for the table to what the
+// conditions of a CHECK constraint allow. Nothing is changed when any of the
+// conditions can't be respected.
+func (t *Table) AddCheckConstraint(constraint CheckConstraint, conditions []Condition) error {
+	for _, condition := range conditions {
+		err := t.validateCondition(condition)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := t.validateValues(conditions)
+	if err != nil {
+		return err
+	}
+
+	t.Metadata.CheckConstraints = append(t.Metadata.CheckConstraints, constraint)
+	for _, condition := range conditions {
+		t.addCondition(constraint.ConstraintName, condition)
+	}
+
+	return nil
+}
+
+func (t *Table) validateCondition(condition Condition) error {
+	i := t.ColumnIndex(condition.ColumnName)
+	if i < 0 {
+		return errors.New("Column '" + condition.ColumnName + "' does not exist")
+	}
+	col := t.Columns[i]
+
+	// Comparisons between columns and to values that must be avoided are
+	// checked once the row has been generated
+	if condition.OtherColumnName != "" {
+		if t.ColumnIndex(condition.OtherColumnName) < 0 {
+			return errors.New("Column '" + condition.OtherColumnName + "' does not exist")
+		}
+
+		return nil
+	}
+
+	if condition.Operator == "IS NOT NULL" {
+		return nil
+	}
+
+	literals := condition.Values
+	if condition.Operator != "IN" {
+		literals = []string{condition.Value}
+	}
+
+	for _, literal := range literals {
+		_, err := Parse(literal)
+		if err != nil {
+			return errors.New("Column '" + col.Name + "' is compared to a value that can't be read: " + literal)
+		}
+	}
+
+	if condition.Operator == "<>" {
+		return nil
+	}
+
+	if _, ok := t.foreignKey(col.Name); ok || col.IsAutoIncrement() || col.DataType == "ARRAY" {
+		return errors.New("Column '" + col.Name + "' can't be limited to the values allowed")
+	}
+
+	switch {
+	case condition.Length:
+		if !generate.IsText(col.DataType) || condition.Operator == "IN" {
+			return errors.New("Column '" + col.Name + "' of type " + col.DataType + " can't have its length limited")
+		}
+
+		_, err := strconv.Atoi(condition.Value)
+		if err != nil {
+			return errors.New("Column '" + col.Name + "' is compared to a length that isn't a whole number: " + condition.Value)
+		}
+	case condition.Operator == "~" || condition.Operator == "~*":
+		value, _ := Parse(condition.Value)
+		_, err := pattern(col, caseInsensitive(condition.Operator, Text(value)))
+		if err != nil {
+			return err
+		}
+	case condition.Operator == "IN" || condition.Operator == "=":
+	default:
+		if !generate.IsNumeric(col.DataType) {
+			return errors.New("Column '" + col.Name + "' of type " + col.DataType + " can't be limited to a range of values")
+		}
+
+		_, err := strconv.ParseFloat(condition.Value, 64)
+		if err != nil {
+			return errors.New("Column '" + col.Name + "' is compared to a value that isn't a number: " + condition.Value)
+		}
+	}
+
+	return nil
+}
+
+// validateValues checks that the columns limited to a list of values by the
+// conditions still have a value to pick once the lists are narrowed down to
+// the values allowed by every constraint
+func (t *Table) validateValues(conditions []Condition) error {
+	allowed := make(map[string][]string)
+	for _, condition := range conditions {
+		if condition.OtherColumnName != "" || condition.Length || (condition.Operator != "IN" && condition.Operator != "=") {
+			continue
+		}
+
+		literals := condition.Values
+		if condition.Operator == "=" {
+			literals = []string{condition.Value}
+		}
+
+		values := make([]string, 0, len(literals))
+		for _, literal := range literals {
+			value, _ := Parse(literal)
+			values = append(values, Text(value))
+		}
+
+		previous, ok := allowed[condition.ColumnName]
+		if options, exists := t.Metadata.ColumnOptions[condition.ColumnName]; !ok && exists && options.Values != nil {
+			previous, ok = make([]string, 0, len(options.Values)), true
+			for _, value := range options.Values {
+				previous = append(previous, Text(value))
+			}
+		}
+
+		if ok {
+			values = slices.DeleteFunc(values, func(value string) bool { return !slices.Contains(previous, value) })
+		}
+
+		if len(values) == 0 {
+			return errors.New("Column '" + condition.ColumnName + "' has no value allowed by every CHECK constraint")
+		}
+
+		allowed[condition.ColumnName] = values
+	}
+
+	return nil
+}
+
+func (t *Table) addCondition(constraintName string, condition Condition) {
+	i := t.ColumnIndex(condition.ColumnName)
+	options := t.columnOptions(condition.ColumnName)
+
+	switch {
+	case condition.OtherColumnName != "" || condition.Operator == "<>":
+		t.Metadata.CheckConditions[constraintName] = append(t.Metadata.CheckConditions[constraintName], condition)
+	case condition.Operator == "IS NOT NULL":
+		t.Columns[i].IsNullable = "NO"
+	case condition.Operator == "~" || condition.Operator == "~*":
+		value, _ := Parse(condition.Value)
+		regex := regexp.MustCompile(caseInsensitive(condition.Operator, Text(value)))
+
+		// Values are generated to match the first pattern, so only the
+		// others and those of templates need to be checked once the row
+		// has been generated
+		if options.Regex == nil && options.Template == nil {
+			options.Regex = regex
+		} else if options.Regex == nil || options.Regex.String() != regex.String() {
+			t.Metadata.CheckConditions[constraintName] = append(t.Metadata.CheckConditions[constraintName], condition)
+		}
+	case condition.Length:
+		n, _ := strconv.Atoi(condition.Value)
+		switch condition.Operator {
+		case "<":
+			n -= 1
+			fallthrough
+		case "<=":
+			if options.MaxLength == 0 || n < options.MaxLength {
+				options.MaxLength = max(n, 0)
+			}
+		case ">":
+			n += 1
+			fallthrough
+		case ">=":
+			options.MinLength = max(options.MinLength, n)
+		case "=":
+			options.MinLength, options.MaxLength = n, n
+		}
+	case condition.Operator == "IN" || condition.Operator == "=":
+		literals := condition.Values
+		if condition.Operator == "=" {
+			literals = []string{condition.Value}
+		}
+
+		values := make([]Value, 0, len(literals))
+		for _, literal := range literals {
+			value, _ := Parse(literal)
+
+			// Only keep the values allowed by every constraint
+			if options.Values == nil || slices.ContainsFunc(options.Values, func(allowed Value) bool { return Text(allowed) == Text(value) }) {
+				values = append(values, value)
+			}
+		}
+
+		options.Values = values
+	default:
+		n, _ := strconv.ParseFloat(condition.Value, 64)
+		bound := &generate.Bound{Value: n, Exclusive: condition.Operator == "<" || condition.Operator == ">"}
+		switch condition.Operator {
+		case ">", ">=":
+			if options.Min == nil || n > options.Min.Value || (n == options.Min.Value && bound.Exclusive) {
+				options.Min = bound
+			}
+		case "<", "<=":
+			if options.Max == nil || n < options.Max.Value || (n == options.Max.Value && bound.Exclusive) {
+				options.Max = bound
+			}
+		}
+	}
+}
+
+// checkRow checks the row against the conditions of the table's CHECK
+// constraints that couldn't be respected while generating it, returning the
+// first constraint it breaks. Values of columns compared to each other are
+// swapped around when that's enough to satisfy the constraint and each value
+// is within the limits of the column it's moved to.
+func (t *Table) checkRow(row []Value) (CheckConstraint, bool) {
+	// A swap can break the conditions already checked, so the conditions
+	// are checked again from the start after each one, with no more swaps
+	// than there are columns so values can't be swapped back and forth
+check:
+	for swaps := 0; ; swaps++ {
+		for _, constraint := range t.Metadata.CheckConstraints {
+			for _, condition := range t.Metadata.CheckConditions[constraint.ConstraintName] {
+				i := t.ColumnIndex(condition.ColumnName)
+				if condition.OtherColumnName == "" {
+					value, _ := Parse(condition.Value)
+					if !holds(row[i], condition.Operator, value) {
+						return constraint, false
+					}
+
+					continue
+				}
+
+				j := t.ColumnIndex(condition.OtherColumnName)
+				if holds(row[i], condition.Operator, row[j]) {
+					continue
+				}
+
+				if swaps < len(t.Columns) && t.swappable(i, j) && t.fits(i, row[j]) && t.fits(j, row[i]) {
+					row[i], row[j] = row[j], row[i]
+					if holds(row[i], condition.Operator, row[j]) {
+						continue check
+					}
+				}
+
+				return constraint, false
+			}
+		}
+
+		return CheckConstraint{}, true
+	}
+}
+
+// fits checks a value against the limits of the column at index i that it
+// was generated within, so that it can be moved over from another column
+func (t *Table) fits(i int, value Value) bool {
+	col := t.Columns[i]
+	if _, ok := value.(Marker); ok {
+		return true
+	}
+
+	if len(col.EnumValues) > 0 && !slices.Contains(col.EnumValues, Text(value)) {
+		return false
+	}
+
+	options, ok := t.Metadata.ColumnOptions[col.Name]
+	if !ok {
+		return true
+	}
+
+	if options.Values != nil && !slices.ContainsFunc(options.Values, func(allowed Value) bool { return Text(allowed) == Text(value) }) {
+		return false
+	}
+
+	if options.Min != nil || options.Max != nil {
+		n, err := generate.ParseBound(col, Text(value))
+		if err == nil {
+			if options.Min != nil && (n < options.Min.Value || (options.Min.Exclusive && n == options.Min.Value)) {
+				return false
+			}
+
+			if options.Max != nil && (n > options.Max.Value || (options.Max.Exclusive && n == options.Max.Value)) {
+				return false
+			}
+		}
+	}
+
+	length := len([]rune(Text(value)))
+	if length < options.MinLength || (options.MaxLength > 0 && length > options.MaxLength) {
+		return false
+	}
+
+	return options.Regex == nil || options.Regex.MatchString(Text(value))
+}
+
+func (t *Table) swappable(i, j int) bool {
+	for _, col := range []Column{t.Columns[i], t.Columns[j]} {
+		if _, ok := t.foreignKey(col.Name); ok || col.IsAutoIncrement() {
+			return false
+		}
+	}
+
+	return t.Columns[i].DataType == t.Columns[j].DataType
+}
+
+// holds checks if a comparison between two values is true. Like the
+// database, comparisons with NULL pass, as do comparisons of values that
+// can't be compared here.
+func holds(left Value, operator string, right Value) bool {
+	if _, ok := left.(Marker); ok {
+		return true
+	} else if _, ok := right.(Marker); ok {
+		return true
+	}
+
+	var order int
+	leftNumber, leftErr := strconv.ParseFloat(Text(left), 64)
+	rightNumber, rightErr := strconv.ParseFloat(Text(right), 64)
+	if leftErr == nil && rightErr == nil {
+		order = cmp.Compare(leftNumber, rightNumber)
+	} else {
+		// Dates and times are written out so they sort as text
+		order = strings.Compare(Text(left), Text(right))
+	}
+
+	switch operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "=":
+		return order == 0
+	case "<>":
+		return order != 0
+	case "~", "~*":
+		matched, err := regexp.MatchString(caseInsensitive(operator, Text(right)), Text(left))
+		return err != nil || matched
+	default:
+		return true
+	}
+}
+
+// pattern compiles the pattern the values of a text column are made to match
+func pattern(col Column, text string) (*regexp.Regexp, error) {
+	if !generate.IsText(col.DataType) {
+		return nil, errors.New("Column '" + col.Name + "' of type " + col.DataType + " can't be made to match a pattern")
+	}
+
+	regex, err := regexp.Compile(text)
+	if err != nil {
+		return nil, errors.New("Column '" + col.Name + "' has a pattern that can't be read: " + err.Error())
+	}
+
+	return regex, nil
+}
+
+// caseInsensitive makes the pattern of a ~* match ignore case
+func caseInsensitive(operator, pattern string) string {
+	if operator == "~*" {
+		return "(?i)" + pattern
+	}
+
+	return pattern
+}
+
+func (t *Table) foreignKey(columnName string) (ForeignKeyRelation, bool) {
+	for _, fk := range t.Metadata.ForeignKeys {
+		if fk.ColumnName == columnName {
+			return fk, true
+		}
+	}
+
+	return ForeignKeyRelation{}, false
+}
+
+func (t *Table) referencedValue(col Column, fk ForeignKeyRelation, references map[string][]Value) (Value, error) {
+	reference, ok := references[fk.ConstraintName]
+	if !ok {
+		if col.IsNullable == "YES" {
+			return Null, nil
+		}
+
+		if t.UniqueReference(fk.ConstraintName) {
+			return nil, errors.New("Column '" + col.Name + "' references '" + fk.QualifiedForeignTableName() + "." + fk.ForeignColumnName + "' through unique FK constraint '" + fk.ConstraintName + "' but every row has already been referenced once, so " + fk.QualifiedForeignTableName() + " needs at least as many rows as " + t.QualifiedName())
+		}
+
+		return nil, errors.New("Column '" + col.Name + "' references '" + fk.QualifiedForeignTableName() + "." + fk.ForeignColumnName + "' through FK constraint '" + fk.ConstraintName + "' but there are no rows to reference")
+	}
+
+	for i, constraintFk := range t.ForeignKeyColumns(fk.ConstraintName) {
+		if constraintFk.ColumnName == col.Name {
+			return reference[i], nil
+		}
+	}
+
+	return nil, errors.New("Column '" + col.Name + "' is missing from FK constraint '" + fk.ConstraintName + "'")
+}
+
+// AddReference keeps the values of the given columns for every row generated
+// from now on so that other tables can reference them
+func (t *Table) AddReference(columnNames []string, inFull bool) {
+	for i, referenced := range t.Metadata.ReferencedColumns {
+		if slices.Equal(referenced, columnNames) {
+			t.Metadata.ReferencedInFull[i] = t.Metadata.ReferencedInFull[i] || inFull
+			return
+		}
+	}
+
+	t.Metadata.ReferencedColumns = append(t.Metadata.ReferencedColumns, columnNames)
+	t.Metadata.ReferencedValues = append(t.Metadata.ReferencedValues, nil)
+	t.Metadata.ReferencedInFull = append(t.Metadata.ReferencedInFull, inFull)
+}
+
+// UniqueReference checks if the columns of the named FK constraint are
+// covered by a unique constraint, so that each of the rows it references
+// can only be referenced once.
+func (t *Table) UniqueReference(constraintName string) bool {
+	var columnNames []string
+	for _, fk := range t.ForeignKeyColumns(constraintName) {
+		columnNames = append(columnNames, fk.ColumnName)
+	}
+
+	for _, constraint := range t.Metadata.UniqueConstraints {
+		covered := len(constraint.ColumnNames) > 0
+		for _, name := range constraint.ColumnNames {
+			covered = covered && slices.Contains(columnNames, name)
+		}
+
+		if covered {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaxReferencedValues is the most rows of a table kept for other rows to
+// reference, past which a random sample of the rows is kept instead
+const MaxReferencedValues = 10000
+
+// sample adds a reference to those kept for the rows generated so far,
+// replacing a random one of them once there are MaxReferencedValues so
+// that every row is as likely to be kept. All of them are kept when
+// inFull is set.
+func sample(references [][]Value, reference []Value, rows int, inFull bool) [][]Value {
+	if inFull || len(references) < MaxReferencedValues {
+		return append(references, reference)
+	}
+
+	if i := gofakeit.IntRange(0, rows-1); i < MaxReferencedValues {
+		references[i] = reference
+	}
+
+	return references
+}
+
+// ReferenceValues returns the values of the given columns kept for every row
+// generated, skipping the rows that can't be referenced
+func (t *Table) ReferenceValues(columnNames []string) [][]Value {
+	for i, referenced := range t.Metadata.ReferencedColumns {
+		if slices.Equal(referenced, columnNames) {
+			return t.Metadata.ReferencedValues[i]
+		}
+	}
+
+	return nil
+}
+
+// addReferences keeps the values of a newly created row that other tables
+// reference
+func (t *Table) addReferences(row []Value) {
+	for i, columnNames := range t.Metadata.ReferencedColumns {
+		var reference []Value
+		for _, name := range columnNames {
+			j := t.ColumnIndex(name)
+			if j < 0 || row[j] == Null || row[j] == Default {
+				reference = nil
+				break
+			}
+
+			reference = append(reference, row[j])
+		}
+
+		if reference != nil {
+			t.Metadata.ReferencedValues[i] = sample(t.Metadata.ReferencedValues[i], reference, t.Metadata.CreatedRows, t.Metadata.ReferencedInFull[i])
+		}
+	}
+}
+
+// addSelfReferences makes a newly created row available to the following
+// rows of a table with self-referencing FK constraints
+func (t *Table) addSelfReferences(row []Value) {
+	for _, fk := range t.Metadata.ForeignKeys {
+		if fk.QualifiedForeignTableName() != t.QualifiedName() {
+			continue
+		}
+
+		constraintFks := t.ForeignKeyColumns(fk.ConstraintName)
+		if constraintFks[0].ColumnName != fk.ColumnName {
+			continue // only add the row once per constraint
+		}
+
+		var reference []Value
+		for _, constraintFk := range constraintFks {
+			value := row[t.ColumnIndex(constraintFk.ForeignColumnName)]
+			if value == Null || value == Default {
+				reference = nil
+				break
+			}
+
+			reference = append(reference, value)
+		}
+
+		if reference != nil {
+			t.Metadata.ForeignKeyValues[fk.ConstraintName] = sample(t.Metadata.ForeignKeyValues[fk.ConstraintName], reference, t.Metadata.CreatedRows, t.UniqueReference(fk.ConstraintName))
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package table
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+
+	"dummy/commands"
+
+	. "dummy/sqldatabase/checkconstraint"
+	. "dummy/sqldatabase/column"
+	. "dummy/sqldatabase/foreignkeyrelation"
+	. "dummy/sqldatabase/uniqueconstraint"
+	. "dummy/sqldatabase/value"
+)
+
+func TestCreateDataUnique(t *testing.T) {
+	gofakeit.Seed(1)
+
+	table := NewTable("flags")
+	table.Columns = []Column{
+		{Name: "enabled", DataType: "boolean", IsNullable: "NO", IsIdentity: "NO"},
+	}
+	table.Metadata.UniqueConstraints = []UniqueConstraint{
+		{ConstraintName: "flags_enabled_key", ColumnNames: []string{"enabled"}},
+	}
+
+	err := table.CreateData(2)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	if table.InsertRows[0][0] == table.InsertRows[1][0] {
+		t.Errorf("Expected the rows to differ, got %v twice", table.InsertRows[0][0])
+	}
+
+	// A boolean only has two values to go around
+	err = table.CreateData(1)
+	if err == nil || !strings.Contains(err.Error(), "flags_enabled_key") {
+		t.Errorf("Expected an error naming the unique constraint, got %v", err)
+	}
+}
+
+func TestCreateDataDistinct(t *testing.T) {
+	gofakeit.Seed(1)
+
+	table := NewTable("accounts")
+	table.Columns = []Column{
+		{Name: "code", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "email", DataType: "character varying", CharacterMaximumLength: sql.NullInt32{Int32: 12, Valid: true}, IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "slot", DataType: "smallint", IsNullable: "NO", IsIdentity: "NO"},
+	}
+	table.Metadata.UniqueConstraints = []UniqueConstraint{
+		{ConstraintName: "accounts_pkey", ColumnNames: []string{"code"}},
+		{ConstraintName: "accounts_email_key", ColumnNames: []string{"email"}},
+		{ConstraintName: "accounts_slot_key", ColumnNames: []string{"slot"}},
+	}
+
+	err := table.Validate(commands.TableCommands{Columns: map[string]commands.ColumnCommands{
+		"email": {Generator: commands.GeneratorCommands{Name: "email"}},
+		"slot":  {Min: "1", Max: "3000"},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	// More rows than there are random words, short emails or slots
+	err = table.CreateData(3000)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	for i := range table.Columns {
+		seen := make(map[Value]bool)
+		for _, row := range table.InsertRows {
+			if seen[row[i]] {
+				t.Fatalf("Expected the values of %s to be unique, got %v twice", table.Columns[i].Name, row[i])
+			}
+			seen[row[i]] = true
+		}
+	}
+
+	for _, row := range table.InsertRows {
+		if email := row[1].(string); len([]rune(email)) > 12 {
+			t.Errorf("Expected the email to fit in 12 characters, got %s", email)
+		}
+
+		if slot := row[2].(int64); slot < 1 || slot > 3000 {
+			t.Errorf("Expected the slot to be between 1 and 3000, got %d", slot)
+		}
+	}
+}
+
+func TestCreateDataCheck(t *testing.T) {
+	gofakeit.Seed(1)
+
+	table := NewTable("bookings")
+	table.Columns = []Column{
+		{Name: "qty", DataType: "integer", IsNullable: "YES", IsIdentity: "NO"},
+		{Name: "starts_at", DataType: "date", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "ends_at", DataType: "date", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	constraint := CheckConstraint{ConstraintName: "bookings_check"}
+	err := table.AddCheckConstraint(constraint, []Condition{
+		{ColumnName: "qty", Operator: "IS NOT NULL"},
+		{ColumnName: "qty", Operator: ">", Value: "0"},
+		{ColumnName: "qty", Operator: "<=", Value: "2"},
+		{ColumnName: "ends_at", Operator: ">", OtherColumnName: "starts_at"},
+	})
+	if err != nil {
+		t.Fatalf("Error calling AddCheckConstraint: %s", err)
+	}
+
+	err = table.CreateData(20)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	if table.Columns[0].IsNullable != "NO" {
+		t.Errorf("Expected qty to no longer be nullable")
+	}
+
+	for _, row := range table.InsertRows {
+		if row[0] != int64(1) && row[0] != int64(2) {
+			t.Errorf("Expected qty to be 1 or 2, got %v", row[0])
+		}
+
+		if Text(row[2]) <= Text(row[1]) {
+			t.Errorf("Expected ends_at %v to come after starts_at %v", row[2], row[1])
+		}
+	}
+}
+
+func TestCreateDataCheckSwap(t *testing.T) {
+	gofakeit.Seed(1)
+
+	table := NewTable("ranges")
+	table.Columns = []Column{
+		{Name: "lo", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "hi", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "k", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "m", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+	}
+	table.Metadata.UniqueConstraints = []UniqueConstraint{{ConstraintName: "ranges_k_key", ColumnNames: []string{"k"}}}
+
+	err := table.Validate(commands.TableCommands{}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.AddCheckConstraint(CheckConstraint{ConstraintName: "ranges_check"}, []Condition{
+		{ColumnName: "lo", Operator: ">=", Value: "500"},
+		{ColumnName: "lo", Operator: "<=", Value: "1000"},
+		{ColumnName: "hi", Operator: ">=", Value: "1"},
+		{ColumnName: "hi", Operator: "<=", Value: "2000"},
+		{ColumnName: "hi", Operator: ">", OtherColumnName: "lo"},
+		{ColumnName: "k", Operator: ">=", Value: "1"},
+		{ColumnName: "k", Operator: "<=", Value: "60"},
+		{ColumnName: "m", Operator: ">=", Value: "1"},
+		{ColumnName: "m", Operator: "<=", Value: "60"},
+		{ColumnName: "k", Operator: "<", OtherColumnName: "m"},
+	})
+	if err != nil {
+		t.Fatalf("Error calling AddCheckConstraint: %s", err)
+	}
+
+	err = table.CreateData(40)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	seen := make(map[Value]bool)
+	for _, row := range table.InsertRows {
+		lo, hi := row[0].(int64), row[1].(int64)
+		if lo < 500 || lo > 1000 || hi <= lo || hi > 2000 {
+			t.Errorf("Expected 500 <= lo < hi <= 2000, got %d and %d", lo, hi)
+		}
+
+		if seen[row[2]] || row[2].(int64) >= row[3].(int64) {
+			t.Errorf("Expected k to be unique and below m, got %v and %v", row[2], row[3])
+		}
+		seen[row[2]] = true
+	}
+}
+
+func TestAddCheckConstraintUnsupported(t *testing.T) {
+	table := NewTable("bookings")
+	table.Columns = []Column{
+		{Name: "qty", DataType: "integer", IsNullable: "YES", IsIdentity: "NO"},
+		{Name: "note", DataType: "text", IsNullable: "YES", IsIdentity: "NO"},
+	}
+
+	err := table.AddCheckConstraint(CheckConstraint{ConstraintName: "bookings_check"}, []Condition{
+		{ColumnName: "qty", Operator: ">", Value: "0"},
+		{ColumnName: "note", Operator: ">", Value: "'a'"},
+	})
+	if err == nil {
+		t.Fatalf("Expected an error comparing a text column to a range")
+	}
+
+	if table.Metadata.ColumnOptions["qty"] != nil && table.Metadata.ColumnOptions["qty"].Min != nil {
+		t.Errorf("Expected none of the conditions to be added")
+	}
+}
+
+func TestAddCheckConstraintNoValues(t *testing.T) {
+	table := NewTable("orders")
+	table.Columns = []Column{
+		{Name: "status", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.AddCheckConstraint(CheckConstraint{ConstraintName: "orders_status_check"}, []Condition{
+		{ColumnName: "status", Operator: "IN", Values: []string{"'open'", "'closed'"}},
+	})
+	if err != nil {
+		t.Fatalf("Error calling AddCheckConstraint: %s", err)
+	}
+
+	err = table.AddCheckConstraint(CheckConstraint{ConstraintName: "orders_status_check1"}, []Condition{
+		{ColumnName: "status", Operator: "=", Value: "'pending'"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error adding a constraint that leaves no values to pick")
+	}
+
+	err = table.AddCheckConstraint(CheckConstraint{ConstraintName: "orders_status_check2"}, []Condition{
+		{ColumnName: "status", Operator: "IN", Values: []string{"'closed'", "'pending'"}},
+		{ColumnName: "status", Operator: "=", Value: "'pending'"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error adding conditions that leave no values to pick")
+	}
+
+	if values := table.Metadata.ColumnOptions["status"].Values; len(values) != 2 {
+		t.Errorf("Expected the values of the first constraint to be kept, got %v", values)
+	}
+}
+
+func TestCreateDataNullRatio(t *testing.T) {
+	gofakeit.Seed(1)
+
+	table := NewTable("people")
+	table.Metadata.NullRatio = 1
+	table.Columns = []Column{
+		{Name: "name", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "nickname", DataType: "text", IsNullable: "YES", IsIdentity: "NO"},
+		{Name: "email", DataType: "text", IsNullable: "YES", IsIdentity: "NO"},
+	}
+
+	never := 0.0
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"email": {NullRatio: &never}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.CreateData(10)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	for _, row := range table.InsertRows {
+		if row[0] == Null || row[1] != Null || row[2] == Null {
+			t.Errorf("Expected only nickname to be NULL, got %v", row)
+		}
+	}
+
+	err = table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"name": {NullRatio: &never}},
+	}, nil)
+	if err == nil {
+		t.Errorf("Expected an error setting a null ratio on a column that isn't nullable")
+	}
+}
+
+func TestValidateDefaultPolicy(t *testing.T) {
+	table := NewTable("todos")
+	table.Columns = []Column{
+		{Name: "task", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"task": {Default: DefaultUse}},
+	}, nil)
+	if err == nil {
+		t.Errorf("Expected an error using the default of a column without one")
+	}
+
+	table.Columns[0].ColumnDefault = sql.NullString{String: "''::text", Valid: true}
+	err = table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"task": {Default: "skip"}},
+	}, nil)
+	if err == nil {
+		t.Errorf("Expected an error using an unknown default policy")
+	}
+}
+
+func TestCreateDataIdentity(t *testing.T) {
+	table := NewTable("todos")
+	table.Columns = []Column{
+		{Name: "id", DataType: "integer", IsNullable: "NO", IsIdentity: "YES", IdentityGeneration: sql.NullString{String: "ALWAYS", Valid: true}},
+		{Name: "position", DataType: "integer", IsNullable: "NO", IsIdentity: "NO", ColumnDefault: sql.NullString{String: "nextval('todos_position_seq'::regclass)", Valid: true}},
+	}
+
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"position": {Identity: IdentityExplicit}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	if len(table.Metadata.IdentityColumns) != 2 {
+		t.Errorf("Expected the serial column to be treated as an identity column, got %v", table.Metadata.IdentityColumns)
+	}
+
+	if table.ExplicitIdentity(table.Columns[0]) || !table.ExplicitIdentity(table.Columns[1]) {
+		t.Errorf("Expected only the position column to be given explicit values")
+	}
+
+	table.Metadata.IdentityValues["position"] = 5
+	err = table.CreateData(2)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	actual := fmt.Sprint(table.InsertRows)
+	expected := "[[DEFAULT 5] [DEFAULT 6]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	err = table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"position": {Identity: "sequence"}},
+	}, nil)
+	if err == nil {
+		t.Errorf("Expected an error using an unknown identity policy")
+	}
+}
+
+func TestStreamData(t *testing.T) {
+	table := NewTable("todos")
+	table.Columns = []Column{
+		{Name: "id", DataType: "integer", IsNullable: "NO", IsIdentity: "YES"},
+	}
+
+	err := table.Validate(commands.TableCommands{BatchSize: 2}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	table.Metadata.IdentityValues["id"] = 1
+	table.AddReference([]string{"id"}, false)
+
+	var batches []string
+	err = table.StreamData(5, func() error {
+		batches = append(batches, fmt.Sprint(table.InsertRows))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error calling StreamData: %s", err)
+	}
+
+	actual := strings.Join(batches, " ")
+	expected := "[[1] [2]] [[3] [4]] [[5]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+
+	if table.InsertRows != nil || table.Metadata.CreatedRows != 5 {
+		t.Errorf("Expected the 5 rows to be dropped once written, got %d left of %d", len(table.InsertRows), table.Metadata.CreatedRows)
+	}
+
+	actual = fmt.Sprint(table.ReferenceValues([]string{"id"}))
+	expected = "[[1] [2] [3] [4] [5]]"
+	if actual != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestStreamDataReferenceSample(t *testing.T) {
+	table := NewTable("users")
+	table.Columns = []Column{
+		{Name: "id", DataType: "integer", IsNullable: "NO", IsIdentity: "YES"},
+		{Name: "email", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.Validate(commands.TableCommands{}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	table.Metadata.IdentityValues["id"] = 1
+	table.AddReference([]string{"id"}, false)
+	table.AddReference([]string{"email"}, true)
+
+	err = table.StreamData(MaxReferencedValues+100, func() error { return nil })
+	if err != nil {
+		t.Fatalf("Error calling StreamData: %s", err)
+	}
+
+	if n := len(table.ReferenceValues([]string{"id"})); n != MaxReferencedValues {
+		t.Errorf("Expected a sample of %d ids to be kept, got %d", MaxReferencedValues, n)
+	}
+
+	if n := len(table.ReferenceValues([]string{"email"})); n != MaxReferencedValues+100 {
+		t.Errorf("Expected every email to be kept, got %d", n)
+	}
+}
+
+func TestUniqueReference(t *testing.T) {
+	table := NewTable("profiles")
+	table.Columns = []Column{
+		{Name: "user_id", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "team_id", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+	}
+	table.Metadata.UniqueConstraints = []UniqueConstraint{{ConstraintName: "profiles_user_id_key", ColumnNames: []string{"user_id"}}}
+
+	err := table.Validate(commands.TableCommands{}, []ForeignKeyRelation{
+		{ConstraintName: "profiles_user_id_fkey", TableName: "profiles", ColumnName: "user_id", ForeignTableName: "users", ForeignColumnName: "id"},
+		{ConstraintName: "profiles_team_id_fkey", TableName: "profiles", ColumnName: "team_id", ForeignTableName: "teams", ForeignColumnName: "id"},
+	})
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	if !table.UniqueReference("profiles_user_id_fkey") || table.UniqueReference("profiles_team_id_fkey") {
+		t.Errorf("Expected only the user to be referenced once")
+	}
+
+	for i := range 500 {
+		table.Metadata.ForeignKeyValues["profiles_user_id_fkey"] = append(table.Metadata.ForeignKeyValues["profiles_user_id_fkey"], []Value{int64(i)})
+	}
+	table.Metadata.ForeignKeyValues["profiles_team_id_fkey"] = [][]Value{{int64(1)}, {int64(2)}}
+
+	err = table.CreateData(500)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	seen := make(map[Value]bool)
+	for _, row := range table.InsertRows {
+		if seen[row[0]] {
+			t.Fatalf("Expected each user to be referenced once, got %v twice", row[0])
+		}
+		seen[row[0]] = true
+	}
+
+	err = table.CreateData(1)
+	if err == nil || !strings.Contains(err.Error(), "profiles_user_id_fkey") {
+		t.Errorf("Expected an error once every user has been referenced, got %v", err)
+	}
+}
+
+func TestCreateDataTemplate(t *testing.T) {
+	table := NewTable("users")
+	table.Columns = []Column{
+		{Name: "email", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "first_name", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "last_name", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{
+			"email":      {Template: "{{lower .first_name}}.{{lower .last_name}}@example.com"},
+			"first_name": {Generator: commands.GeneratorCommands{Name: "firstname"}},
+			"last_name":  {Generator: commands.GeneratorCommands{Name: "lastname"}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.CreateData(3)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	// The email is made from the names that come after it
+	for _, row := range table.InsertRows {
+		expected := strings.ToLower(Text(row[1]) + "." + Text(row[2]) + "@example.com")
+		if Text(row[0]) != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, Text(row[0]))
+		}
+	}
+}
+
+func TestCreateDataCheckPattern(t *testing.T) {
+	table := NewTable("products")
+	table.Columns = []Column{
+		{Name: "sku", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "postcode", DataType: "character varying", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{"postcode": {Regex: `^\d{5}$`}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.AddCheckConstraint(CheckConstraint{ConstraintName: "products_sku_check"}, []Condition{
+		{ColumnName: "sku", Operator: "~*", Value: `'^sku-[a-z]{3}$'`},
+	})
+	if err != nil {
+		t.Fatalf("Error calling AddCheckConstraint: %s", err)
+	}
+
+	err = table.CreateData(10)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	for _, row := range table.InsertRows {
+		if !regexp.MustCompile(`(?i)^sku-[a-z]{3}$`).MatchString(Text(row[0])) {
+			t.Errorf("Expected sku to match the CHECK constraint, got %v", row[0])
+		}
+
+		if !regexp.MustCompile(`^\d{5}$`).MatchString(Text(row[1])) {
+			t.Errorf("Expected postcode to match its regex, got %v", row[1])
+		}
+	}
+
+	err = table.AddCheckConstraint(CheckConstraint{ConstraintName: "products_sku_check"}, []Condition{
+		{ColumnName: "sku", Operator: "~", Value: `'(unclosed'`},
+	})
+	if err == nil {
+		t.Errorf("Expected an error using a pattern that can't be read")
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	table := NewTable("orders")
+	table.Columns = []Column{
+		{Name: "note", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "total", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "placed_at", DataType: "date", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	err := table.Validate(commands.TableCommands{
+		Columns: map[string]commands.ColumnCommands{
+			"total": {Distribution: &commands.DistributionCommands{Buckets: []commands.BucketCommands{
+				{Min: "-10", Max: "-1", Weight: 1},
+				{Min: "100", Max: "200", Weight: 3},
+			}}},
+			"placed_at": {Min: "2024-01-01", Max: "2024-01-31", Distribution: &commands.DistributionCommands{Type: "exponential", Toward: "max"}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error calling Validate: %s", err)
+	}
+
+	err = table.CreateData(100)
+	if err != nil {
+		t.Fatalf("Error calling CreateData: %s", err)
+	}
+
+	for _, row := range table.InsertRows {
+		total := row[1].(int64)
+		if total < -10 || (total > -1 && total < 100) || total > 200 {
+			t.Errorf("Expected total to be within one of its buckets, got %v", row[1])
+		}
+
+		if Text(row[2]) < "2024-01-01" || Text(row[2]) > "2024-01-31" {
+			t.Errorf("Expected placed_at to be in January 2024, got %v", row[2])
+		}
+	}
+
+	for _, cmd := range []commands.ColumnCommands{
+		{Min: "1"},
+		{Max: "yesterday"},
+		{Min: "2024-02-01", Max: "2024-01-01"},
+		{Distribution: &commands.DistributionCommands{Type: "poisson"}},
+		{Distribution: &commands.DistributionCommands{Type: "normal", StdDev: "-1d"}},
+		{Distribution: &commands.DistributionCommands{Type: "zipf", Toward: "middle"}},
+		{Min: "2024-01-01", Distribution: &commands.DistributionCommands{Buckets: []commands.BucketCommands{{Min: "2023-01-01", Max: "2024-01-31", Weight: 1}}}},
+	} {
+		name := "placed_at"
+		if cmd.Min == "1" {
+			name = "note"
+		}
+
+		err = table.Validate(commands.TableCommands{Columns: map[string]commands.ColumnCommands{name: cmd}}, nil)
+		if err == nil {
+			t.Errorf("Expected an error validating %+v", cmd)
+		}
+	}
+}
+
+func TestGuessCustomTextFieldGenerators(t *testing.T) {
+	table := NewTable("people")
+	table.Columns = []Column{
+		{Name: "first_name", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "nickname", DataType: "text", IsNullable: "NO", IsIdentity: "NO"},
+		{Name: "age", DataType: "integer", IsNullable: "NO", IsIdentity: "NO"},
+	}
+
+	table.GuessCustomTextFieldGenerators()
+
+	if options, ok := table.Metadata.ColumnOptions["first_name"]; !ok || options.Generator != "firstname" {
+		t.Errorf("Expected first_name to be filled in with first names")
+	}
+
+	if _, ok := table.Metadata.ColumnOptions["age"]; ok {
+		t.Errorf("Expected no options to be added for age")
+	}
+}
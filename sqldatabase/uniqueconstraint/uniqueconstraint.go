@@ -0,0 +1,11 @@
+package uniqueconstraint
+
+// UniqueConstraint is a primary key, unique constraint or unique index
+// covering one or more of a table's columns.
+type UniqueConstraint struct {
+	TableSchema    string
+	TableName      string
+	ConstraintName string
+	ColumnNames    []string
+	IsPrimaryKey   bool
+}
@@ -0,0 +1,197 @@
+// Package value holds the typed values generated for columns, which each
+// driver and output format writes out in its own syntax.
+package value
+
+import (
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Value is a value generated for a column. It's one of:
+//
+//   - Null or Default
+//   - bool
+//   - int64
+//   - Number, for decimal numbers that need to be written out exactly
+//   - string, which also holds dates, ranges, geometric types and the like
+//     written the way Postgres prints them
+//   - Bits, for bit strings
+//   - []byte
+//   - Array
+type Value any
+
+// Marker stands in for a value when there isn't one
+type Marker string
+
+const (
+	Null    Marker = "NULL"    // the column is left NULL
+	Default Marker = "DEFAULT" // the database fills in the column's default
+)
+
+// Number is a decimal number kept as it's written so no precision is lost
+type Number string
+
+// Bits is a string of 0s and 1s
+type Bits string
+
+// Array holds the elements of an array, with an Array in place of each
+// element for multidimensional arrays
+type Array struct {
+	// ElementType is the name of the type of the elements, quoted and
+	// qualified by its schema when needed, to cast the array to
+	ElementType string
+	Dimensions  int
+	Elements    []Value
+}
+
+// Text writes a value the way Postgres would print it, with arrays in the
+// {a,b} form. Null and Default are left empty.
+func Text(value Value) string {
+	switch value := value.(type) {
+	case bool:
+		return strconv.FormatBool(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case Number:
+		return string(value)
+	case string:
+		return value
+	case Bits:
+		return string(value)
+	case []byte:
+		return `\x` + hex.EncodeToString(value)
+	case Array:
+		var text strings.Builder
+		text.WriteRune('{')
+		for i, element := range value.Elements {
+			if i > 0 {
+				text.WriteRune(',')
+			}
+
+			switch element.(type) {
+			case Marker:
+				text.WriteString("NULL")
+			case bool, int64, Number, Array:
+				text.WriteString(Text(element))
+			default:
+				text.WriteString(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(Text(element)) + `"`)
+			}
+		}
+
+		text.WriteRune('}')
+		return text.String()
+	}
+
+	return ""
+}
+
+// casts matches the casts at the end of a literal, like ::text or
+// ::"char"[]
+var casts = regexp.MustCompile(`(::("[^"]*"|[a-z_][a-z0-9_$ ]*)(\.("[^"]*"|[a-z_][a-z0-9_$]*))?(\([0-9, ]*\))?(\[\])*)+$`)
+
+// number matches the number at the start of a literal
+var number = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?(e[+-]?[0-9]+)?`)
+
+// Parse reads a SQL literal, like those found in CHECK constraints, as a
+// value. Casts are dropped since the column's type says the same.
+func Parse(literal string) (Value, error) {
+	literal = casts.ReplaceAllString(literal, "")
+
+	value, rest, err := parseValue(literal)
+	if err != nil || rest != "" {
+		return nil, errors.New("Cannot read the value " + literal)
+	}
+
+	return value, nil
+}
+
+// parseValue reads the value at the start of a literal, returning whatever
+// follows it
+func parseValue(literal string) (Value, string, error) {
+	switch {
+	case strings.HasPrefix(literal, "NULL"):
+		return Null, literal[len("NULL"):], nil
+	case strings.HasPrefix(literal, "DEFAULT"):
+		return Default, literal[len("DEFAULT"):], nil
+	case strings.HasPrefix(literal, "'"):
+		return parseQuoted(literal)
+	case strings.HasPrefix(literal, "B'"), strings.HasPrefix(literal, "b'"):
+		bits, rest, err := parseQuoted(literal[1:])
+		return Bits(bits), rest, err
+	case strings.HasPrefix(literal, "ARRAY["):
+		return parseArray(literal[len("ARRAY"):])
+	case strings.HasPrefix(literal, "["):
+		return parseArray(literal)
+	case strings.HasPrefix(literal, "true"):
+		return true, literal[len("true"):], nil
+	case strings.HasPrefix(literal, "false"):
+		return false, literal[len("false"):], nil
+	}
+
+	digits := number.FindString(literal)
+	if digits == "" {
+		return nil, "", errors.New("Cannot read the value " + literal)
+	}
+
+	if n, err := strconv.ParseInt(digits, 10, 64); err == nil {
+		return n, literal[len(digits):], nil
+	}
+
+	return Number(digits), literal[len(digits):], nil
+}
+
+// parseQuoted reads the single quoted string at the start of a literal
+func parseQuoted(literal string) (string, string, error) {
+	var value strings.Builder
+	for i := 1; i < len(literal); i++ {
+		if literal[i] != '\'' {
+			value.WriteByte(literal[i])
+			continue
+		}
+
+		if i+1 < len(literal) && literal[i+1] == '\'' {
+			value.WriteByte('\'')
+			i++
+			continue
+		}
+
+		return value.String(), literal[i+1:], nil
+	}
+
+	return "", "", errors.New("Unterminated string " + literal)
+}
+
+// parseArray reads the bracketed elements of an array, nesting an Array for
+// each sub-array
+func parseArray(literal string) (Array, string, error) {
+	array := Array{Dimensions: 1, Elements: make([]Value, 0)}
+
+	rest := literal[1:]
+	for i := 0; !strings.HasPrefix(rest, "]"); i++ {
+		if i > 0 {
+			if !strings.HasPrefix(rest, ",") {
+				return Array{}, "", errors.New("Cannot read the array " + literal)
+			}
+
+			rest = rest[1:]
+		}
+
+		var element Value
+		var err error
+		element, rest, err = parseValue(rest)
+		if err != nil {
+			return Array{}, "", err
+		}
+
+		if sub, ok := element.(Array); ok {
+			array.Dimensions = sub.Dimensions + 1
+		}
+
+		array.Elements = append(array.Elements, element)
+	}
+
+	return array, rest[1:], nil
+}
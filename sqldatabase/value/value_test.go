@@ -0,0 +1,36 @@
+package value
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"NULL":                           "",
+		"-12.5":                          "-12.5",
+		"true":                           "true",
+		"B'0101'":                        "0101",
+		"'it''s'::character varying(20)": "it's",
+		`ARRAY[['a\b',NULL],['"c"','d']]::text[][]`: `{{"a\\b",NULL},{"\"c\"","d"}}`,
+	}
+
+	for literal, expected := range cases {
+		value, err := Parse(literal)
+		if err != nil {
+			t.Errorf("Error calling Parse on %s: %s", literal, err)
+			continue
+		}
+
+		if Text(value) != expected {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, Text(value))
+		}
+	}
+
+	array, _ := Parse("ARRAY[[1,2],[3,4]]")
+	if array.(Array).Dimensions != 2 {
+		t.Errorf("Expected a two dimensional array, got %v", array)
+	}
+
+	_, err := Parse("now()")
+	if err == nil {
+		t.Errorf("Expected an error reading an expression")
+	}
+}
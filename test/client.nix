@@ -13,7 +13,21 @@
     options:
       seed: 1
     tables:
-      - name: todos
+      - name: testing.todos
         count: 3
   '';
+
+  environment.etc."dummy-fk.yml".text = ''
+    server:
+      host: "postgres"
+      name: "postgres"
+      user: "postgres"
+    options:
+      seed: 1
+    tables:
+      - name: testing.projects
+        count: 3
+      - name: testing.tasks
+        count: 10
+  '';
 }
@@ -6,3 +6,18 @@ CREATE TABLE testing.todos (
     complete BOOLEAN NOT NULL DEFAULT false,
     created_at TIMESTAMP
 );
+
+CREATE TABLE testing.projects (
+    owner TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    PRIMARY KEY (owner, slug)
+);
+
+-- The key's columns are listed in a different order to the table's so that
+-- pairing them up by anything but their position in the key shows up
+CREATE TABLE testing.tasks (
+    id INT GENERATED ALWAYS AS IDENTITY,
+    project_slug TEXT NOT NULL,
+    project_owner TEXT NOT NULL,
+    FOREIGN KEY (project_owner, project_slug) REFERENCES testing.projects (owner, slug)
+);